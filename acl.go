@@ -0,0 +1,157 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ACLUser contains info about ACL user
+type ACLUser struct {
+	Flags     []string
+	Passwords []string
+	Commands  string
+	Keys      []string
+	Channels  []string
+	Selectors []*ACLSelector
+}
+
+// ACLSelector contains info about ACL user selector
+type ACLSelector struct {
+	Commands string
+	Keys     []string
+	Channels []string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var ErrWrongACLResponse = errors.New("ACL GETUSER command response must have Array type")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ParseACLUser parses ACL GETUSER output. Keys and channels are returned as
+// lists of rules both for Redis 6 (array replies) and Redis 7+ (string replies).
+func ParseACLUser(r *Resp) (*ACLUser, error) {
+	if r.HasType(NIL) {
+		return nil, ErrRespNil
+	}
+
+	if !r.HasType(ARRAY) {
+		return nil, ErrWrongACLResponse
+	}
+
+	items, err := r.Array()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items)%2 != 0 {
+		return nil, ErrNotMap
+	}
+
+	user := &ACLUser{}
+
+	for i := 0; i < len(items); i += 2 {
+		field, err := items[i].Str()
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse ACL data: %v", err)
+		}
+
+		value := items[i+1]
+
+		switch strings.ToLower(field) {
+		case "flags":
+			user.Flags, err = value.List()
+		case "passwords":
+			user.Passwords, err = value.List()
+		case "commands":
+			user.Commands, err = value.Str()
+		case "keys":
+			user.Keys, err = parseACLRules(value)
+		case "channels":
+			user.Channels, err = parseACLRules(value)
+		case "selectors":
+			user.Selectors, err = parseACLSelectors(value)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse ACL field %q: %v", field, err)
+		}
+	}
+
+	return user, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseACLRules parses list of rules which can be represented as array
+// (Redis 6) or space-separated string (Redis 7+)
+func parseACLRules(r *Resp) ([]string, error) {
+	if r.HasType(ARRAY) {
+		return r.List()
+	}
+
+	rules, err := r.Str()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(rules), nil
+}
+
+// parseACLSelectors parses list of selectors
+func parseACLSelectors(r *Resp) ([]*ACLSelector, error) {
+	items, err := r.Array()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*ACLSelector
+
+	for _, item := range items {
+		fields, err := item.Array()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(fields)%2 != 0 {
+			return nil, ErrNotMap
+		}
+
+		selector := &ACLSelector{}
+
+		for i := 0; i < len(fields); i += 2 {
+			field, err := fields[i].Str()
+
+			if err != nil {
+				return nil, err
+			}
+
+			switch strings.ToLower(field) {
+			case "commands":
+				selector.Commands, err = fields[i+1].Str()
+			case "keys":
+				selector.Keys, err = parseACLRules(fields[i+1])
+			case "channels":
+				selector.Channels, err = parseACLRules(fields[i+1])
+			}
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result = append(result, selector)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// BitUnit is unit of range used by BITCOUNT and BITPOS commands
+type BitUnit uint8
+
+// Range units for BITCOUNT and BITPOS commands
+const (
+	// BIT_UNIT_DEFAULT doesn't send unit (Redis uses BYTE by default)
+	BIT_UNIT_DEFAULT BitUnit = iota
+
+	// BIT_UNIT_BYTE defines range in bytes
+	BIT_UNIT_BYTE
+
+	// BIT_UNIT_BIT defines range in bits (Redis 7+)
+	BIT_UNIT_BIT
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// GetBit returns the bit value at offset in the string value stored at key
+func (c *Client) GetBit(key string, offset int64) (int, error) {
+	return c.Cmd("GETBIT", key, offset).Int()
+}
+
+// SetBit sets or clears the bit at offset in the string value stored at key and
+// returns the original bit value
+func (c *Client) SetBit(key string, offset int64, value int) (int, error) {
+	return c.Cmd("SETBIT", key, offset, value).Int()
+}
+
+// BitCount counts the number of set bits in the given range of the string value
+// stored at key. Use start 0 and end -1 for counting bits in the whole string.
+func (c *Client) BitCount(key string, start, end int64, unit BitUnit) (int64, error) {
+	args := append([]any{key, start, end}, unit.args()...)
+	return c.Cmd("BITCOUNT", args...).Int64()
+}
+
+// BitPos returns the position of the first bit set to 1 or 0 in the given range
+// of the string value stored at key. Use start 0 and end -1 for searching in
+// the whole string.
+func (c *Client) BitPos(key string, bit int, start, end int64, unit BitUnit) (int64, error) {
+	args := append([]any{key, bit, start, end}, unit.args()...)
+	return c.Cmd("BITPOS", args...).Int64()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// args returns command arguments for unit
+func (u BitUnit) args() []any {
+	switch u {
+	case BIT_UNIT_BYTE:
+		return []any{"BYTE"}
+	case BIT_UNIT_BIT:
+		return []any{"BIT"}
+	}
+
+	return nil
+}
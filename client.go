@@ -3,10 +3,13 @@ package redy
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
 	"net"
+	"sync/atomic"
 	"time"
 )
 
@@ -29,84 +32,241 @@ type Client struct {
 	DialTimeout  time.Duration
 	LastCritical error
 
+	// PipeTimeout is maximum time for reading all replies of pipeline batch in
+	// PipeResp. Unlike ReadTimeout, which is applied to every reply separately
+	// (so reading N replies may take up to N*ReadTimeout), PipeTimeout limits
+	// the whole batch.
+	PipeTimeout time.Duration
+
+	// TLSConfigFunc is function which returns TLS configuration for the given
+	// address at dial time. If it returns nil, TLSConfig is used.
+	TLSConfigFunc func(addr string) *tls.Config
+
+	// DialFunc is custom function used for dialing instead of built-in
+	// dial logic. If TLSConfig is set, TLS connection is established over
+	// connection returned by this function.
+	DialFunc func(network, addr string) (net.Conn, error)
+
+	// OnReconnect is callback which is called after every attempt to re-dial
+	// Redis by client which has been connected before. Attempt number starts
+	// from 1, err is nil if attempt was successful.
+	OnReconnect func(attempt int, err error)
+
+	// OnConnect is callback which is called after every successful connect
+	// (including reconnects) and reset. It can be used for running setup commands like
+	// SELECT or CLIENT SETNAME. If callback returns error, connection is closed
+	// and error is returned by Connect.
+	OnConnect func(c *Client) error
+
+	// Reconnects is number of successful reconnects
+	Reconnects int64
+
+	// StrictEncoding enables returning error for arguments of unsupported types
+	// instead of encoding them using fmt.Sprint
+	StrictEncoding bool
+
+	// MaxPipelineDepth is maximum number of calls in the pipeline queue. If
+	// the queue reaches it, PipeAppend sends queued calls to Redis. Zero value
+	// means no limit.
+	MaxPipelineDepth int
+
+	// LargeReplySize is soft limit for size of bulk strings (in bytes) and
+	// arrays (in elements) in replies exceeding which OnLargeReply is called.
+	// It allows to detect growing keys before replies exceed hard limit and
+	// can't be parsed.
+	LargeReplySize int64
+
+	// OnLargeReply is callback which is called if size of bulk string or array
+	// in reply exceeds LargeReplySize
+	OnLargeReply func(size int64)
+
+	// ReadBufferSize is size of connection read buffer (0 = 4KB). Larger buffer
+	// reduces number of reads (see ConnStats) for workloads with large replies.
+	ReadBufferSize int
+
+	// DisableNoDelay enables Nagle's algorithm for TCP connections. By default
+	// TCP_NODELAY is set, so small commands are sent without delay.
+	DisableNoDelay bool
+
+	// KeepRaw enables capturing of raw reply bytes (see Resp.Raw)
+	KeepRaw bool
+
+	wasConnected  bool
+	closed        bool
+	replyMode     string
+	batchDeadline time.Time
+
 	conn         net.Conn
+	stats        ConnStats
 	respReader   *RespReader
 	writeScratch []byte
 	writeBuf     *bytes.Buffer
 
+	lastReq       req
 	pending       []req
 	completed     []*Resp
 	completedHead []*Resp
 }
 
+// ConnStats contains connection I/O statistics
+type ConnStats struct {
+	BytesRead    int64 // Total number of bytes read from connection
+	BytesWritten int64 // Total number of bytes written to connection
+	Reads        int64 // Number of read calls to connection
+}
+
+// statsReader is connection reader which collects I/O statistics
+type statsReader struct {
+	client *Client
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // Errors
 var (
-	ErrEmptyPipeline = errors.New("Pipeline is empty")
-	ErrNotConnected  = errors.New("Client not connected")
+	ErrEmptyPipeline  = errors.New("Pipeline is empty")
+	ErrNotConnected   = errors.New("Client not connected")
+	ErrUnexpectedResp = errors.New("Unexpected response")
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+const (
+	// loadingRetryDelay is delay between attempts used by CmdWaitReady
+	loadingRetryDelay = 100 * time.Millisecond
+
+	// drainTimeout is time to wait for more data used by Drain
+	drainTimeout = 10 * time.Millisecond
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewUnixClient creates new client for connecting to Redis via UNIX socket
+func NewUnixClient(path string) *Client {
+	return &Client{Network: "unix", Addr: path}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
 // Connect connect to Redis instance
 func (c *Client) Connect() error {
+	return c.connect(1)
+}
+
+// ConnectRetry tries to connect to Redis instance up to given number of
+// attempts. Delay between attempts starts from given backoff and doubles after
+// every failed attempt. Returns error from the last attempt if all attempts
+// failed.
+func (c *Client) ConnectRetry(attempts int, backoff time.Duration) error {
 	var err error
 
-	if c.Network == "" {
-		c.Network = "tcp"
+	if attempts < 1 {
+		attempts = 1
 	}
 
-	switch {
-	case c.TLSConfig != nil:
-		c.conn, err = tls.Dial(c.Network, c.Addr, c.TLSConfig)
-	case c.DialTimeout > 0:
-		c.conn, err = net.DialTimeout(c.Network, c.Addr, c.DialTimeout)
-	default:
-		c.conn, err = net.Dial(c.Network, c.Addr)
+	for i := 0; i < attempts; i++ {
+		if i > 0 && backoff > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		err = c.connect(i + 1)
+
+		if err == nil {
+			return nil
+		}
 	}
 
-	if err != nil {
-		return err
+	return err
+}
+
+// Cmd calls the given Redis command
+func (c *Client) Cmd(cmd string, args ...any) *Resp {
+	if c.conn == nil {
+		resp := errToResp(ERR_IO, ErrNotConnected)
+		return &resp
 	}
 
-	c.respReader = NewRespReader(c.conn)
+	err := c.writeRequest(req{cmd, args})
 
-	// if write buffer already exist just clear it and reuse
-	if c.writeBuf != nil {
-		c.writeBuf.Reset()
-	} else {
-		c.writeBuf = bytes.NewBuffer(make([]byte, 0, 128))
+	if err != nil {
+		resp := errToResp(ERR_IO, err)
+		return &resp
 	}
 
-	completed := make([]*Resp, 0, 10)
+	return c.readReply()
+}
 
-	c.completed = completed
-	c.completedHead = completed
+// CmdWaitReady calls the given Redis command and retries it while Redis is
+// loading the dataset in memory (LOADING error) until the given timeout is
+// reached. Reply with LOADING error is returned if Redis is still loading after
+// timeout.
+func (c *Client) CmdWaitReady(timeout time.Duration, cmd string, args ...any) *Resp {
+	deadline := getDeadline(timeout)
 
-	return nil
+	for {
+		resp := c.Cmd(cmd, args...)
+
+		if resp.ErrPrefix() != "LOADING" {
+			return resp
+		}
+
+		delay := time.Until(deadline)
+
+		if delay <= 0 {
+			return resp
+		}
+
+		if delay > loadingRetryDelay {
+			delay = loadingRetryDelay
+		}
+
+		time.Sleep(delay)
+	}
 }
 
-// Cmd calls the given Redis command
-func (c *Client) Cmd(cmd string, args ...any) *Resp {
+// CmdRaw sends the given pre-encoded RESP command as is and reads one reply
+func (c *Client) CmdRaw(encoded []byte) *Resp {
 	if c.conn == nil {
 		resp := errToResp(ERR_IO, ErrNotConnected)
 		return &resp
 	}
 
-	err := c.writeRequest(req{cmd, args})
+	err := c.writeRaw(encoded)
 
 	if err != nil {
 		resp := errToResp(ERR_IO, err)
 		return &resp
 	}
 
-	return c.readResp(true)
+	return c.readReply()
 }
 
-// PipeAppend adds the given call to the pipeline queue
+// Do calls the given Redis command and decodes reply using given decoder
+// (e.g. (*Resp).Int64)
+func Do[T any](c *Client, decode func(*Resp) (T, error), cmd string, args ...any) (T, error) {
+	return decode(c.Cmd(cmd, args...))
+}
+
+// PipeAppend adds the given call to the pipeline queue. If MaxPipelineDepth is
+// set and the queue reaches it, queued calls are sent to Redis and their replies
+// are read, so they can be retrieved later through PipeResp.
 func (c *Client) PipeAppend(cmd string, args ...any) {
 	c.pending = append(c.pending, req{cmd, args})
+
+	if c.MaxPipelineDepth <= 0 || len(c.pending) < c.MaxPipelineDepth || c.conn == nil {
+		return
+	}
+
+	nreqs := len(c.pending)
+	err := c.pipeFlush()
+
+	if err != nil {
+		for i := 0; i < nreqs; i++ {
+			resp := errToResp(ERR_IO, err)
+			c.completed = append(c.completed, &resp)
+		}
+	}
 }
 
 // PipeResp returns the reply for the next request in the pipeline queue
@@ -127,21 +287,76 @@ func (c *Client) PipeResp() *Resp {
 		return &resp
 	}
 
-	nreqs := len(c.pending)
-	err := c.writeRequest(c.pending...)
-
-	c.pending = nil
+	err := c.pipeFlush()
 
 	if err != nil {
 		resp := errToResp(ERR_IO, err)
 		return &resp
 	}
 
-	c.completed = c.completedHead
+	return c.PipeResp()
+}
 
-	for i := 0; i < nreqs; i++ {
-		resp := c.readResp(true)
-		c.completed = append(c.completed, resp)
+// PipeRespContext returns the reply for the next request in the pipeline queue.
+// Unlike PipeResp, sending queued calls and reading their replies is bounded by
+// the given context. If context is already done, queued calls are discarded
+// without sending. If context is done before all replies are read,
+// the connection is closed, all queued replies are discarded and reply with
+// context error is returned.
+func (c *Client) PipeRespContext(ctx context.Context) *Resp {
+	if c.conn == nil || len(c.completed) > 0 || len(c.pending) == 0 {
+		return c.PipeResp()
+	}
+
+	if ctx.Err() != nil {
+		c.pending = nil
+		resp := errToResp(ERR_IO, ctx.Err())
+		return &resp
+	}
+
+	var canceled bool
+
+	conn := c.conn
+	done, stopped := make(chan struct{}), make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		select {
+		case <-ctx.Done():
+			canceled = true
+			// Interrupt blocked I/O, connection is closed only if flush
+			// was actually interrupted
+			conn.SetDeadline(time.Now())
+		case <-done:
+		}
+	}()
+
+	err := c.pipeFlush()
+
+	close(done)
+	<-stopped
+
+	if canceled {
+		if err == nil && !c.closed {
+			// Context was done after all replies had been read
+			conn.SetDeadline(time.Time{})
+			return c.PipeResp()
+		}
+
+		conn.Close()
+
+		c.completed = nil
+		c.closed = true
+		c.LastCritical = ctx.Err()
+
+		resp := errToResp(ERR_IO, ctx.Err())
+		return &resp
+	}
+
+	if err != nil {
+		resp := errToResp(ERR_IO, err)
+		return &resp
 	}
 
 	return c.PipeResp()
@@ -166,7 +381,17 @@ func (c *Client) PipeClear() (int, int) {
 
 // GetConfig read and parse full in-memory config
 func (c *Client) GetConfig(configCommand string) (*Config, error) {
-	resp := c.Cmd(configCommand, "GET", "*")
+	return c.GetConfigMatching(configCommand, "*")
+}
+
+// GetConfigMatching read and parse in-memory config properties matching given
+// glob-style patterns. Multiple patterns are supported only by Redis 7+.
+func (c *Client) GetConfigMatching(configCommand string, patterns ...string) (*Config, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"*"}
+	}
+
+	resp := c.Cmd(configCommand, "GET", patterns)
 
 	if resp.Err != nil {
 		return nil, resp.Err
@@ -175,22 +400,415 @@ func (c *Client) GetConfig(configCommand string) (*Config, error) {
 	return ParseConfig(resp)
 }
 
-// Close closes the connection
+// ConfigResetStat resets the statistics reported by INFO command
+func (c *Client) ConfigResetStat(configCommand string) error {
+	return okToErr(c.Cmd(configCommand, "RESETSTAT"))
+}
+
+// ConfigRewrite rewrites configuration file with in-memory configuration
+func (c *Client) ConfigRewrite(configCommand string) error {
+	return okToErr(c.Cmd(configCommand, "REWRITE"))
+}
+
+// Reset resets connection state using RESET command (Redis 6.2+). Command
+// aborts transaction, unsubscribes from all channels, deauthenticates connection,
+// turns replies on and selects DB 0. All commands queued by PipeAppend and not retrieved replies
+// are discarded. After successful reset OnConnect callback is called again for
+// restoring connection setup, its error is returned as is.
+func (c *Client) Reset() error {
+	c.PipeClear()
+	c.replyMode = ""
+
+	resp := c.Cmd("RESET")
+
+	if resp.Err != nil {
+		return resp.Err
+	}
+
+	status, err := resp.Str()
+
+	if err != nil || status != "RESET" {
+		return ErrUnexpectedResp
+	}
+
+	if c.OnConnect != nil {
+		return c.OnConnect(c)
+	}
+
+	return nil
+}
+
+// Drain discards all buffered and immediately available data from
+// the connection. It can be used for re-synchronizing the stream after
+// a parsing error. All replies which have yet to be retrieved through PipeResp
+// are discarded. If data can't be read, client reconnects to Redis.
+func (c *Client) Drain() error {
+	if c.conn == nil {
+		return ErrNotConnected
+	}
+
+	c.completed = nil
+	c.respReader.r.Discard(c.respReader.r.Buffered())
+
+	buf := make([]byte, 512)
+
+	for {
+		c.conn.SetReadDeadline(getDeadline(drainTimeout))
+
+		_, err := c.conn.Read(buf)
+
+		if err == nil {
+			continue
+		}
+
+		netErr, ok := err.(net.Error)
+
+		if ok && netErr.Timeout() {
+			c.conn.SetReadDeadline(time.Time{})
+			return nil
+		}
+
+		c.conn.Close()
+
+		return c.Connect()
+	}
+}
+
+// Recover resets client to a known-good state after critical error. It closes
+// the connection (if it's still open), discards all queued commands and replies,
+// clears LastCritical and reconnects to Redis running OnConnect callback.
+func (c *Client) Recover() error {
+	if c.conn != nil && !c.closed {
+		c.Close()
+	}
+
+	c.PipeClear()
+	c.LastCritical = nil
+
+	return c.Connect()
+}
+
+// Close closes the connection immediately. All commands queued by PipeAppend
+// are discarded, use Shutdown for graceful closing.
 func (c *Client) Close() error {
+	if c.conn == nil {
+		return ErrNotConnected
+	}
+
+	c.closed = true
+
 	return c.conn.Close()
 }
 
+// isHealthy returns true if client is connected and connection wasn't closed
+func (c *Client) isHealthy() bool {
+	return c != nil && c.conn != nil && !c.closed
+}
+
+// Shutdown gracefully closes the connection. If there are commands queued by
+// PipeAppend, they are sent to Redis and their replies are drained (best effort)
+// before closing. Replies which have yet to be retrieved through PipeResp are
+// discarded.
+func (c *Client) Shutdown() error {
+	if c.conn == nil {
+		return ErrNotConnected
+	}
+
+	pending := c.pending
+
+	c.pending = nil
+	c.completed = nil
+
+	if len(pending) != 0 {
+		err := c.writeRequest(pending...)
+
+		if err != nil {
+			return err
+		}
+
+		for range pending {
+			resp := c.readReply()
+
+			if resp.HasType(ERR_IO) {
+				return resp.Err
+			}
+		}
+	}
+
+	return c.Close()
+}
+
+// LastCommand returns name and arguments of the last command sent to Redis
+// by Cmd or PipeResp. It can be used for logging command which caused critical
+// error (see LastCritical).
+func (c *Client) LastCommand() (string, []any) {
+	if c == nil {
+		return "", nil
+	}
+
+	return c.lastReq.cmd, c.lastReq.args
+}
+
+// Stats returns connection I/O statistics
+func (c *Client) Stats() ConnStats {
+	if c == nil {
+		return ConnStats{}
+	}
+
+	return c.stats
+}
+
+// LocalAddr returns local network address of the connection
+func (c *Client) LocalAddr() net.Addr {
+	if c == nil || c.conn == nil {
+		return nil
+	}
+
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr returns remote network address of the connection
+func (c *Client) RemoteAddr() net.Addr {
+	if c == nil || c.conn == nil {
+		return nil
+	}
+
+	return c.conn.RemoteAddr()
+}
+
+// IsTLS returns true if connection uses TLS
+func (c *Client) IsTLS() bool {
+	if c == nil || c.conn == nil {
+		return false
+	}
+
+	_, ok := c.conn.(*tls.Conn)
+
+	return ok
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+func (c *Client) connect(attempt int) error {
+	var err error
+
+	if c.Network == "" {
+		c.Network = "tcp"
+	}
+
+	c.conn, err = c.dial()
+
+	if c.wasConnected {
+		if err == nil {
+			atomic.AddInt64(&c.Reconnects, 1)
+		}
+
+		if c.OnReconnect != nil {
+			c.OnReconnect(attempt, err)
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	c.wasConnected = true
+	c.closed = false
+	c.replyMode = ""
+
+	if c.ReadBufferSize > 0 {
+		c.respReader = NewRespReader(bufio.NewReaderSize(&statsReader{c}, c.ReadBufferSize))
+	} else {
+		c.respReader = NewRespReader(&statsReader{c})
+	}
+
+	c.respReader.KeepRaw = c.KeepRaw
+
+	c.setNoDelay()
+
+	// if write buffer already exist just clear it and reuse
+	if c.writeBuf != nil {
+		c.writeBuf.Reset()
+	} else {
+		c.writeBuf = bytes.NewBuffer(make([]byte, 0, 128))
+	}
+
+	if c.writeScratch == nil {
+		c.writeScratch = make([]byte, 0, 64)
+	}
+
+	completed := make([]*Resp, 0, 10)
+
+	c.completed = completed
+	c.completedHead = completed
+
+	if c.OnConnect != nil {
+		err = c.OnConnect(c)
+
+		if err != nil {
+			c.Close()
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) dial() (net.Conn, error) {
+	var err error
+	var conn net.Conn
+
+	tlsConfig := c.TLSConfig
+
+	if c.TLSConfigFunc != nil {
+		config := c.TLSConfigFunc(c.Addr)
+
+		if config != nil {
+			tlsConfig = config
+		}
+	}
+
+	switch {
+	case c.DialFunc != nil:
+		conn, err = c.DialFunc(c.Network, c.Addr)
+	case tlsConfig != nil && !c.isUnixSocket():
+		dialer := &net.Dialer{Timeout: c.DialTimeout}
+		tlsConn, err := tls.DialWithDialer(dialer, c.Network, c.Addr, tlsConfig)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return tlsConn, nil
+	case c.DialTimeout > 0:
+		conn, err = net.DialTimeout(c.Network, c.Addr, c.DialTimeout)
+	default:
+		conn, err = net.Dial(c.Network, c.Addr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig == nil {
+		return conn, nil
+	}
+
+	if c.DialTimeout > 0 {
+		conn.SetDeadline(getDeadline(c.DialTimeout))
+	}
+
+	tlsConn := tls.Client(conn, c.getTLSConfig(tlsConfig))
+	err = tlsConn.Handshake()
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if c.DialTimeout > 0 {
+		conn.SetDeadline(time.Time{})
+	}
+
+	return tlsConn, nil
+}
+
+// getTLSConfig returns TLS configuration with server name derived from
+// the address if it's not set (same as tls.Dial does). Server name is never
+// derived from UNIX socket path.
+func (c *Client) getTLSConfig(tlsConfig *tls.Config) *tls.Config {
+	if tlsConfig.ServerName != "" || c.isUnixSocket() {
+		return tlsConfig
+	}
+
+	host, _, err := net.SplitHostPort(c.Addr)
+
+	if err != nil {
+		host = c.Addr
+	}
+
+	config := tlsConfig.Clone()
+	config.ServerName = host
+
+	return config
+}
+
+// setNoDelay configures TCP_NODELAY option of TCP connection
+func (c *Client) setNoDelay() {
+	conn := c.conn
+	tlsConn, ok := conn.(*tls.Conn)
+
+	if ok {
+		conn = tlsConn.NetConn()
+	}
+
+	tcpConn, ok := conn.(*net.TCPConn)
+
+	if ok {
+		tcpConn.SetNoDelay(!c.DisableNoDelay)
+	}
+}
+
+// isUnixSocket returns true if client uses UNIX socket for connection
+func (c *Client) isUnixSocket() bool {
+	return c.Network == "unix" || c.Network == "unixpacket"
+}
+
+// pipeFlush sends all queued calls to Redis and reads their replies
+func (c *Client) pipeFlush() error {
+	nreqs := len(c.pending)
+	err := c.writeRequest(c.pending...)
+
+	c.pending = nil
+
+	if err != nil {
+		return err
+	}
+
+	if len(c.completed) == 0 {
+		c.completed = c.completedHead
+	}
+
+	if c.PipeTimeout > 0 {
+		c.batchDeadline = getDeadline(c.PipeTimeout)
+	}
+
+	for i := 0; i < nreqs; i++ {
+		resp := c.readReply()
+		c.completed = append(c.completed, resp)
+	}
+
+	if !c.batchDeadline.IsZero() {
+		c.batchDeadline = time.Time{}
+
+		if c.ReadTimeout <= 0 && c.conn != nil {
+			c.conn.SetReadDeadline(time.Time{})
+		}
+	}
+
+	return nil
+}
+
 func (c *Client) writeRequest(requests ...req) error {
-	if c.ReadTimeout != 0 {
-		c.conn.SetReadDeadline(getDeadline(c.WriteTimeout))
+	if c.StrictEncoding {
+		for _, r := range requests {
+			err := checkArgType(r.args)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.WriteTimeout > 0 {
+		c.conn.SetWriteDeadline(getDeadline(c.WriteTimeout))
 	}
 
 	var err error
 
 MAINLOOP:
 	for _, r := range requests {
+		c.lastReq = r
 		c.writeBuf.Reset()
 		elems := flattenedLength(r.args...) + 1
 
@@ -214,7 +832,10 @@ MAINLOOP:
 			}
 		}
 
-		_, err = c.writeBuf.WriteTo(c.conn)
+		var n int64
+
+		n, err = c.writeBuf.WriteTo(c.conn)
+		c.stats.BytesWritten += n
 
 		if err != nil {
 			break MAINLOOP
@@ -229,11 +850,53 @@ MAINLOOP:
 	return err
 }
 
+func (c *Client) writeRaw(data []byte) error {
+	if c.WriteTimeout > 0 {
+		c.conn.SetWriteDeadline(getDeadline(c.WriteTimeout))
+	}
+
+	n, err := c.conn.Write(data)
+	c.stats.BytesWritten += int64(n)
+
+	if err != nil {
+		c.LastCritical = err
+		c.Close()
+	}
+
+	return err
+}
+
+// readReply reads reply for sent command taking into account reply mode
+func (c *Client) readReply() *Resp {
+	switch c.replyMode {
+	case REPLY_OFF:
+		return &Resp{typ: NIL}
+	case REPLY_SKIP:
+		c.replyMode = ""
+		return &Resp{typ: NIL}
+	}
+
+	return c.readResp(true)
+}
+
 func (c *Client) readResp(strict bool) *Resp {
-	if c.ReadTimeout != 0 {
-		c.conn.SetReadDeadline(getDeadline(c.ReadTimeout))
+	var deadline time.Time
+
+	if c.ReadTimeout > 0 {
+		deadline = getDeadline(c.ReadTimeout)
+	}
+
+	if !c.batchDeadline.IsZero() && (deadline.IsZero() || c.batchDeadline.Before(deadline)) {
+		deadline = c.batchDeadline
+	}
+
+	if !deadline.IsZero() {
+		c.conn.SetReadDeadline(deadline)
 	}
 
+	c.respReader.LargeReplySize = c.LargeReplySize
+	c.respReader.OnLargeReply = c.OnLargeReply
+
 	resp := c.respReader.Read()
 
 	if resp.HasType(ERR_IO) && (strict || !isTimeout(resp)) {
@@ -244,6 +907,16 @@ func (c *Client) readResp(strict bool) *Resp {
 	return resp
 }
 
+// Read reads data from client connection
+func (r *statsReader) Read(p []byte) (int, error) {
+	n, err := r.client.conn.Read(p)
+
+	r.client.stats.Reads++
+	r.client.stats.BytesRead += int64(n)
+
+	return n, err
+}
+
 func getDeadline(timeout time.Duration) time.Time {
 	return time.Now().Add(timeout)
 }
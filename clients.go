@@ -0,0 +1,232 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Client pause modes
+const (
+	PAUSE_ALL   = "ALL"
+	PAUSE_WRITE = "WRITE"
+)
+
+// Client reply modes
+const (
+	REPLY_ON   = "ON"
+	REPLY_OFF  = "OFF"
+	REPLY_SKIP = "SKIP"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ClientConn contains info about client connection from CLIENT LIST or
+// CLIENT INFO output
+type ClientConn struct {
+	ID        int64
+	Addr      string
+	LocalAddr string
+	Name      string
+	Age       time.Duration
+	Idle      time.Duration
+	Flags     string
+	DB        int
+	Sub       int
+	PSub      int
+	Multi     int
+	Cmd       string
+	User      string
+	Resp      int
+	LibName   string
+	LibVer    string
+
+	// Fields contains all fields from the output
+	Fields map[string]string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Errors
+var (
+	ErrClientNotFound   = errors.New("Client not found")
+	ErrUnknownReplyMode = errors.New("Unknown reply mode")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ParseClientList parses CLIENT LIST output
+func ParseClientList(r *Resp) ([]*ClientConn, error) {
+	data, err := r.Str()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*ClientConn
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if line == "" {
+			continue
+		}
+
+		result = append(result, parseClientConn(line))
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ClientInfo returns info about current client connection (Redis 6.2+)
+func (c *Client) ClientInfo() (*ClientConn, error) {
+	data, err := c.Cmd("CLIENT", "INFO").Str()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseClientConn(strings.TrimRight(data, "\r\n")), nil
+}
+
+// ClientKillByID closes connection of client with given ID
+func (c *Client) ClientKillByID(id int64) error {
+	n, err := c.Cmd("CLIENT", "KILL", "ID", id).Int64()
+
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return ErrClientNotFound
+	}
+
+	return nil
+}
+
+// ClientKillByAddr closes connections of clients with given address (ip:port)
+// and returns number of closed connections
+func (c *Client) ClientKillByAddr(addr string) (int64, error) {
+	return c.Cmd("CLIENT", "KILL", "ADDR", addr).Int64()
+}
+
+// ClientPause suspends all clients for given duration. Mode can be PAUSE_ALL,
+// PAUSE_WRITE or empty (Redis uses ALL by default).
+func (c *Client) ClientPause(d time.Duration, mode string) error {
+	args := []any{"PAUSE", d.Milliseconds()}
+
+	if mode != "" {
+		args = append(args, strings.ToUpper(mode))
+	}
+
+	return okToErr(c.Cmd("CLIENT", args...))
+}
+
+// ClientUnpause resumes processing of all clients paused by ClientPause
+func (c *Client) ClientUnpause() error {
+	return okToErr(c.Cmd("CLIENT", "UNPAUSE"))
+}
+
+// ClientNoEvict enables or disables eviction of current client connection
+func (c *Client) ClientNoEvict(enable bool) error {
+	return okToErr(c.Cmd("CLIENT", "NO-EVICT", onOff(enable)))
+}
+
+// SetReplyMode sets reply mode of connection (REPLY_ON, REPLY_OFF or
+// REPLY_SKIP). While replies are disabled, Cmd and PipeResp don't read replies
+// and return empty NIL replies, so errors are not reported. REPLY_SKIP disables
+// reply only for the next command.
+func (c *Client) SetReplyMode(mode string) error {
+	mode = strings.ToUpper(mode)
+
+	switch mode {
+	case REPLY_ON:
+		c.replyMode = ""
+		return okToErr(c.Cmd("CLIENT", "REPLY", REPLY_ON))
+
+	case REPLY_OFF, REPLY_SKIP:
+		if c.conn == nil {
+			return ErrNotConnected
+		}
+
+		err := c.writeRequest(req{"CLIENT", []any{"REPLY", mode}})
+
+		if err != nil {
+			return err
+		}
+
+		if c.replyMode != REPLY_OFF {
+			c.replyMode = mode
+		}
+
+		return nil
+	}
+
+	return ErrUnknownReplyMode
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// onOff converts boolean to ON/OFF argument
+func onOff(v bool) string {
+	if v {
+		return "ON"
+	}
+
+	return "OFF"
+}
+
+// parseClientConn parses single line with info about client connection
+func parseClientConn(data string) *ClientConn {
+	conn := &ClientConn{Fields: make(map[string]string)}
+
+	for _, field := range strings.Fields(data) {
+		k, v, _ := strings.Cut(field, "=")
+		conn.Fields[k] = v
+
+		switch k {
+		case "id":
+			conn.ID, _ = strconv.ParseInt(v, 10, 64)
+		case "addr":
+			conn.Addr = v
+		case "laddr":
+			conn.LocalAddr = v
+		case "name":
+			conn.Name = v
+		case "age":
+			age, _ := strconv.Atoi(v)
+			conn.Age = time.Duration(age) * time.Second
+		case "idle":
+			idle, _ := strconv.Atoi(v)
+			conn.Idle = time.Duration(idle) * time.Second
+		case "flags":
+			conn.Flags = v
+		case "db":
+			conn.DB, _ = strconv.Atoi(v)
+		case "sub":
+			conn.Sub, _ = strconv.Atoi(v)
+		case "psub":
+			conn.PSub, _ = strconv.Atoi(v)
+		case "multi":
+			conn.Multi, _ = strconv.Atoi(v)
+		case "cmd":
+			conn.Cmd = v
+		case "user":
+			conn.User = v
+		case "resp":
+			conn.Resp, _ = strconv.Atoi(v)
+		case "lib-name":
+			conn.LibName = v
+		case "lib-ver":
+			conn.LibVer = v
+		}
+	}
+
+	return conn
+}
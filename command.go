@@ -0,0 +1,112 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// CommandSpec contains info about Redis command
+type CommandSpec struct {
+	Name     string
+	Arity    int
+	Flags    []string
+	FirstKey int
+	LastKey  int
+	Step     int
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var ErrWrongCommandResponse = errors.New("COMMAND command response must have Array type")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ParseCommandInfo parses COMMAND or COMMAND INFO output. Nil entries (returned
+// for unknown commands) are skipped.
+func ParseCommandInfo(r *Resp) ([]CommandSpec, error) {
+	if !r.HasType(ARRAY) {
+		return nil, ErrWrongCommandResponse
+	}
+
+	items, err := r.Array()
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CommandSpec, 0, len(items))
+
+	for _, item := range items {
+		if item.HasType(NIL) {
+			continue
+		}
+
+		spec, err := parseCommandSpec(item)
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse COMMAND data: %v", err)
+		}
+
+		result = append(result, spec)
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func parseCommandSpec(r *Resp) (CommandSpec, error) {
+	fields, err := r.Array()
+
+	if err != nil {
+		return CommandSpec{}, err
+	}
+
+	if len(fields) < 6 {
+		return CommandSpec{}, errors.New("Not enough fields in command info")
+	}
+
+	spec := CommandSpec{}
+
+	spec.Name, err = fields[0].Str()
+
+	if err != nil {
+		return CommandSpec{}, err
+	}
+
+	spec.Arity, err = fields[1].Int()
+
+	if err != nil {
+		return CommandSpec{}, err
+	}
+
+	spec.Flags, err = fields[2].List()
+
+	if err != nil {
+		return CommandSpec{}, err
+	}
+
+	spec.FirstKey, err = fields[3].Int()
+
+	if err != nil {
+		return CommandSpec{}, err
+	}
+
+	spec.LastKey, err = fields[4].Int()
+
+	if err != nil {
+		return CommandSpec{}, err
+	}
+
+	spec.Step, err = fields[5].Int()
+
+	if err != nil {
+		return CommandSpec{}, err
+	}
+
+	return spec, nil
+}
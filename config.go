@@ -5,6 +5,7 @@ package redy
 import (
 	"bufio"
 	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -20,7 +21,11 @@ type Config struct {
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-var ErrWrongConfResponse = errors.New("CONFIG command response must have Array type")
+// Errors
+var (
+	ErrWrongConfResponse = errors.New("CONFIG command response must have Array type")
+	ErrWrongConfigItems  = errors.New("Wrong number of items in CONFIG response")
+)
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
@@ -145,7 +150,7 @@ func parseInMemoryConfig(r *Resp) (*Config, error) {
 	itemsNum := len(items)
 
 	if itemsNum%2 != 0 {
-		return nil, errors.New("Wrong number of items in CONFIG response")
+		return nil, ErrWrongConfigItems
 	}
 
 	config := &Config{
@@ -154,8 +159,21 @@ func parseInMemoryConfig(r *Resp) (*Config, error) {
 	}
 
 	for i := 0; i < itemsNum; i += 2 {
-		prop, _ := items[i].Str()
-		value, _ := items[i+1].Str()
+		prop, err := items[i].Str()
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse CONFIG property name: %w", err)
+		}
+
+		var value string
+
+		if !items[i+1].HasType(NIL) {
+			value, err = items[i+1].Str()
+
+			if err != nil {
+				return nil, fmt.Errorf("Can't parse CONFIG property %q value: %w", prop, err)
+			}
+		}
 
 		config.Props = append(config.Props, prop)
 		config.Data[prop] = []string{value}
@@ -47,3 +47,69 @@ func FuzzRespReader(data []byte) int {
 
 	return 1
 }
+
+func FuzzRespReaderFull(data []byte) int {
+	rr := NewRespReader(bytes.NewReader(data))
+	rr.MaxDepth = fuzzMaxDepth
+
+	var count int
+
+	for {
+		r := rr.Read()
+
+		if r.HasType(ERR_IO) {
+			break
+		}
+
+		if respDepth(r) > fuzzMaxDepth {
+			panic("MaxDepth limit is not honored")
+		}
+
+		count++
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	return 1
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const fuzzMaxDepth = 16
+
+func respDepth(r *Resp) int {
+	var maxDepth int
+
+	if items, ok := r.val.([]Resp); ok {
+		for i := range items {
+			d := respDepth(&items[i])
+
+			if d > maxDepth {
+				maxDepth = d
+			}
+		}
+
+		maxDepth++
+	}
+
+	// Attributes are container on the same level as reply they are attached to
+	if len(r.attrs) != 0 {
+		var attrsDepth int
+
+		for _, v := range r.attrs {
+			d := respDepth(v)
+
+			if d > attrsDepth {
+				attrsDepth = d
+			}
+		}
+
+		if attrsDepth+1 > maxDepth {
+			maxDepth = attrsDepth + 1
+		}
+	}
+
+	return maxDepth
+}
@@ -0,0 +1,183 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var ErrWrongScanResponse = errors.New("SCAN command response must contain cursor and array of items")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Special hash field TTL values returned by HTTL
+const (
+	// TTL_NO_EXPIRE means that field exists but has no associated expiration
+	TTL_NO_EXPIRE time.Duration = -1
+
+	// TTL_NOT_EXIST means that field or key doesn't exist
+	TTL_NOT_EXIST time.Duration = -2
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// HGetAllScan returns all fields and values of the hash stored at key. Unlike
+// HGETALL it iterates over the hash using HSCAN with given batch size (COUNT),
+// so it doesn't block the server on huge hashes. Since HSCAN provides weaker
+// guarantees than HGETALL, fields modified during iteration may be missing or
+// contain either old or new value.
+func (c *Client) HGetAllScan(key string, batch int) (map[string]string, error) {
+	cursor := "0"
+	result := make(map[string]string)
+
+	for {
+		args := []any{key, cursor}
+
+		if batch > 0 {
+			args = append(args, "COUNT", batch)
+		}
+
+		next, items, err := parseScanResp(c.Cmd("HSCAN", args...))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(items)%2 != 0 {
+			return nil, ErrNotMap
+		}
+
+		for i := 0; i < len(items); i += 2 {
+			result[items[i]] = items[i+1]
+		}
+
+		if next == "0" {
+			return result, nil
+		}
+
+		cursor = next
+	}
+}
+
+// HRandField returns up to count random fields from the hash stored at key
+// (Redis 6.2+). If withValues is false, all values in the map are empty. Since
+// the result is a map, duplicates returned for negative count are merged.
+func (c *Client) HRandField(key string, count int, withValues bool) (map[string]string, error) {
+	if withValues {
+		return c.Cmd("HRANDFIELD", key, count, "WITHVALUES").Map()
+	}
+
+	fields, err := c.Cmd("HRANDFIELD", key, count).List()
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(fields))
+
+	for _, field := range fields {
+		result[field] = ""
+	}
+
+	return result, nil
+}
+
+// HExpire sets timeout on given fields of the hash stored at key (Redis 7.4+).
+// If timeout contains fractions of second, HPEXPIRE is used instead of HEXPIRE.
+// Returns status code for every field: -2 if field doesn't exist, 0 if timeout
+// wasn't set due to condition, 1 if timeout was set and 2 if field was deleted
+// because timeout is in the past.
+func (c *Client) HExpire(key string, ttl time.Duration, fields ...string) ([]int, error) {
+	cmd, ttlVal := "HEXPIRE", int64(ttl/time.Second)
+
+	if ttl%time.Second != 0 {
+		cmd, ttlVal = "HPEXPIRE", ttl.Milliseconds()
+	}
+
+	return parseIntList(c.Cmd(cmd, key, ttlVal, "FIELDS", len(fields), fields))
+}
+
+// HTTL returns remaining time to live of given fields of the hash stored at key
+// (Redis 7.4+). Fields without timeout are reported as TTL_NO_EXPIRE and missing
+// fields as TTL_NOT_EXIST, so check for these values before using TTL as
+// duration.
+func (c *Client) HTTL(key string, fields ...string) ([]time.Duration, error) {
+	ttls, err := parseIntList(c.Cmd("HTTL", key, "FIELDS", len(fields), fields))
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]time.Duration, len(ttls))
+
+	for i, ttl := range ttls {
+		switch ttl {
+		case -1:
+			result[i] = TTL_NO_EXPIRE
+		case -2:
+			result[i] = TTL_NOT_EXIST
+		default:
+			result[i] = time.Duration(ttl) * time.Second
+		}
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseIntList parses array of integers
+func parseIntList(r *Resp) ([]int, error) {
+	items, err := r.Array()
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]int, len(items))
+
+	for i, item := range items {
+		result[i], err = item.Int()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// parseScanResp parses reply of SCAN-family command and returns next cursor
+// and items
+func parseScanResp(r *Resp) (string, []string, error) {
+	if r.Err != nil {
+		return "", nil, r.Err
+	}
+
+	parts, err := r.Array()
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(parts) != 2 {
+		return "", nil, ErrWrongScanResponse
+	}
+
+	cursor, err := parts[0].Str()
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	items, err := parts[1].List()
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return cursor, items, nil
+}
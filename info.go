@@ -5,8 +5,10 @@ package redy
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -47,6 +49,53 @@ type ReplicaInfo struct {
 	Lag    int64
 }
 
+// PersistenceInfo contains info about RDB and AOF persistence
+type PersistenceInfo struct {
+	RDBLastSaveTime         time.Time
+	RDBChangesSinceLastSave int64
+	RDBBgsaveInProgress     bool
+	AOFEnabled              bool
+	AOFRewriteInProgress    bool
+	LoadingInProgress       bool
+}
+
+// CPUInfo contains info about CPU consumption
+type CPUInfo struct {
+	UsedCPUSys          float64
+	UsedCPUUser         float64
+	UsedCPUSysChildren  float64
+	UsedCPUUserChildren float64
+}
+
+// StatsInfo contains general statistics
+type StatsInfo struct {
+	TotalConnectionsReceived uint64
+	TotalCommandsProcessed   uint64
+	InstantaneousOpsPerSec   uint64
+	KeyspaceHits             uint64
+	KeyspaceMisses           uint64
+	ExpiredKeys              uint64
+	EvictedKeys              uint64
+	RejectedConnections      uint64
+}
+
+// ClientsInfo contains info about client connections
+type ClientsInfo struct {
+	ConnectedClients            int64
+	ClusterConnections          int64
+	MaxClients                  int64
+	BlockedClients              int64
+	TrackingClients             int64
+	ClientsInTimeoutTable       int64
+	ClientRecentMaxInputBuffer  uint64
+	ClientRecentMaxOutputBuffer uint64
+}
+
+// ClusterInfo contains info about cluster mode
+type ClusterInfo struct {
+	Enabled bool
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 var defaultFieldsSeparators = []string{":"}
@@ -65,6 +114,11 @@ func ParseInfo(r *Resp) (*Info, error) {
 		return nil, fmt.Errorf("Can't parse INFO data: %v", err)
 	}
 
+	return ParseInfoString(rawInfo)
+}
+
+// ParseInfoString parses INFO command output stored as a plain string
+func ParseInfoString(rawInfo string) (*Info, error) {
 	info, err := parseRedisInfo(rawInfo)
 
 	if err != nil {
@@ -74,6 +128,28 @@ func ParseInfo(r *Resp) (*Info, error) {
 	return info, nil
 }
 
+// HitRatioDelta calculates keyspace hit ratio (0.0-1.0) over the interval
+// between two INFO snapshots. Returns NaN if any snapshot doesn't contain Stats
+// section or counters were reset (e.g. due to restart or CONFIG RESETSTAT)
+// between snapshots, and 0 if there were no lookups in the interval.
+func HitRatioDelta(prev, cur *Info) float64 {
+	prevStats, curStats := prev.Stats(), cur.Stats()
+
+	switch {
+	case prevStats == nil || curStats == nil,
+		curStats.KeyspaceHits < prevStats.KeyspaceHits,
+		curStats.KeyspaceMisses < prevStats.KeyspaceMisses:
+		return math.NaN()
+	}
+
+	delta := &StatsInfo{
+		KeyspaceHits:   curStats.KeyspaceHits - prevStats.KeyspaceHits,
+		KeyspaceMisses: curStats.KeyspaceMisses - prevStats.KeyspaceMisses,
+	}
+
+	return delta.HitRatio()
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // Flatten flatten info data
@@ -98,6 +174,16 @@ func (i *Info) Flatten() [][2]string {
 	return result
 }
 
+// Section returns section with given name (case-insensitive) or nil if there is
+// no such section
+func (i *Info) Section(name string) *InfoSection {
+	if i == nil {
+		return nil
+	}
+
+	return i.Sections[strings.ToLower(name)]
+}
+
 // Get returns field value as string
 func (i *Info) Get(section string, fields ...string) string {
 	if i == nil || section == "" || len(fields) == 0 {
@@ -121,12 +207,13 @@ func (i *Info) Get(section string, fields ...string) string {
 	return ""
 }
 
-// GetB returns field value as boolean
+// GetB returns field value as boolean. Values 1, ok, yes, true, up and enabled
+// (case-insensitive) are treated as true, all other values as false.
 func (i *Info) GetB(section string, fields ...string) bool {
 	rs := i.Get(section, fields...)
 
 	switch strings.ToLower(rs) {
-	case "1", "ok":
+	case "1", "ok", "yes", "true", "up", "enabled":
 		return true
 	}
 
@@ -172,9 +259,14 @@ func (i *Info) GetU(section string, fields ...string) uint64 {
 	return ru
 }
 
-// Is checks if field value is equals to given value
+// Is checks if field value is equals to given value. Strings are compared
+// case-insensitively. Values of any other type are formatted with "%s" verb
+// before comparison, so types which can't be formatted as strings (e.g. int32)
+// never match.
 func (i *Info) Is(section string, field string, value any) bool {
 	switch t := value.(type) {
+	case string:
+		return strings.EqualFold(i.Get(section, field), t)
 	case bool:
 		return i.GetB(section, field) == t
 	case int:
@@ -187,11 +279,20 @@ func (i *Info) Is(section string, field string, value any) bool {
 		return i.GetU(section, field) == t
 	}
 
-	return i.Get(section, field) == fmt.Sprintf("%s", value)
+	return strings.EqualFold(i.Get(section, field), fmt.Sprintf("%s", value))
+}
+
+// ReplicaCount returns number of connected replicas
+func (i *Info) ReplicaCount() int {
+	return i.GetI("Replication", "connected_slaves")
 }
 
 // GetReplicaInfo parses and returns info about connected replica with given index
 func (i *Info) GetReplicaInfo(index int) *ReplicaInfo {
+	if index < 0 || index >= i.ReplicaCount() {
+		return nil
+	}
+
 	rawInfo := i.Get("Replication",
 		"slave"+strconv.Itoa(index),
 		"replica"+strconv.Itoa(index),
@@ -201,11 +302,11 @@ func (i *Info) GetReplicaInfo(index int) *ReplicaInfo {
 		return nil
 	}
 
-	ip := readField(rawInfo, 1, false, "=", ",")
-	port := readField(rawInfo, 3, false, "=", ",")
-	state := readField(rawInfo, 5, false, "=", ",")
-	offset := readField(rawInfo, 7, false, "=", ",")
-	lag := readField(rawInfo, 9, false, "=", ",")
+	ip := readNamedField(rawInfo, "ip")
+	port := readNamedField(rawInfo, "port")
+	state := readNamedField(rawInfo, "state")
+	offset := readNamedField(rawInfo, "offset")
+	lag := readNamedField(rawInfo, "lag")
 
 	portInt, _ := strconv.Atoi(port)
 	offsetInt, _ := strconv.ParseInt(offset, 10, 64)
@@ -220,6 +321,102 @@ func (i *Info) GetReplicaInfo(index int) *ReplicaInfo {
 	}
 }
 
+// Clients returns parsed info from Clients section
+func (i *Info) Clients() *ClientsInfo {
+	if !i.hasSection("Clients") {
+		return nil
+	}
+
+	return &ClientsInfo{
+		ConnectedClients:            int64(i.GetI("Clients", "connected_clients")),
+		ClusterConnections:          int64(i.GetI("Clients", "cluster_connections")),
+		MaxClients:                  int64(i.GetI("Clients", "maxclients")),
+		BlockedClients:              int64(i.GetI("Clients", "blocked_clients")),
+		TrackingClients:             int64(i.GetI("Clients", "tracking_clients")),
+		ClientsInTimeoutTable:       int64(i.GetI("Clients", "clients_in_timeout_table")),
+		ClientRecentMaxInputBuffer:  i.GetU("Clients", "client_recent_max_input_buffer"),
+		ClientRecentMaxOutputBuffer: i.GetU("Clients", "client_recent_max_output_buffer"),
+	}
+}
+
+// Cluster returns parsed info from Cluster section
+func (i *Info) Cluster() *ClusterInfo {
+	if !i.hasSection("Cluster") {
+		return nil
+	}
+
+	return &ClusterInfo{
+		Enabled: i.GetB("Cluster", "cluster_enabled"),
+	}
+}
+
+// ClusterEnabled returns true if cluster mode is enabled
+func (i *Info) ClusterEnabled() bool {
+	cluster := i.Cluster()
+	return cluster != nil && cluster.Enabled
+}
+
+// Persistence returns parsed info from Persistence section
+func (i *Info) Persistence() *PersistenceInfo {
+	if !i.hasSection("Persistence") {
+		return nil
+	}
+
+	lastSave := int64(i.GetI("Persistence", "rdb_last_save_time"))
+
+	return &PersistenceInfo{
+		RDBLastSaveTime:         time.Unix(lastSave, 0),
+		RDBChangesSinceLastSave: int64(i.GetI("Persistence", "rdb_changes_since_last_save")),
+		RDBBgsaveInProgress:     i.GetB("Persistence", "rdb_bgsave_in_progress"),
+		AOFEnabled:              i.GetB("Persistence", "aof_enabled"),
+		AOFRewriteInProgress:    i.GetB("Persistence", "aof_rewrite_in_progress"),
+		LoadingInProgress:       i.GetB("Persistence", "loading"),
+	}
+}
+
+// CPU returns parsed info from CPU section
+func (i *Info) CPU() *CPUInfo {
+	if !i.hasSection("CPU") {
+		return nil
+	}
+
+	return &CPUInfo{
+		UsedCPUSys:          i.GetF("CPU", "used_cpu_sys"),
+		UsedCPUUser:         i.GetF("CPU", "used_cpu_user"),
+		UsedCPUSysChildren:  i.GetF("CPU", "used_cpu_sys_children"),
+		UsedCPUUserChildren: i.GetF("CPU", "used_cpu_user_children"),
+	}
+}
+
+// Stats returns parsed info from Stats section
+func (i *Info) Stats() *StatsInfo {
+	if !i.hasSection("Stats") {
+		return nil
+	}
+
+	return &StatsInfo{
+		TotalConnectionsReceived: i.GetU("Stats", "total_connections_received"),
+		TotalCommandsProcessed:   i.GetU("Stats", "total_commands_processed"),
+		InstantaneousOpsPerSec:   i.GetU("Stats", "instantaneous_ops_per_sec"),
+		KeyspaceHits:             i.GetU("Stats", "keyspace_hits"),
+		KeyspaceMisses:           i.GetU("Stats", "keyspace_misses"),
+		ExpiredKeys:              i.GetU("Stats", "expired_keys"),
+		EvictedKeys:              i.GetU("Stats", "evicted_keys"),
+		RejectedConnections:      i.GetU("Stats", "rejected_connections"),
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// HitRatio calculates keyspace hit ratio (0.0-1.0)
+func (s *StatsInfo) HitRatio() float64 {
+	if s == nil || s.KeyspaceHits+s.KeyspaceMisses == 0 {
+		return 0.0
+	}
+
+	return float64(s.KeyspaceHits) / float64(s.KeyspaceHits+s.KeyspaceMisses)
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // Keys calculates number of keys
@@ -246,6 +443,17 @@ func (k *KeyspaceInfo) Expires() uint64 {
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+// hasSection returns true if info contains section with given name
+func (i *Info) hasSection(section string) bool {
+	if i == nil {
+		return false
+	}
+
+	_, ok := i.Sections[strings.ToLower(section)]
+
+	return ok
+}
+
 // codebeat:disable[ABC,LOC]
 
 func parseRedisInfo(rawInfo string) (*Info, error) {
@@ -285,14 +493,14 @@ func parseRedisInfo(rawInfo string) (*Info, error) {
 				continue
 			}
 
-			v := readField(sectionName, 1, false)
+			// values may contain colons (e.g. IPv6 addresses), so split
+			// only by the first one
+			k, v, _ := strings.Cut(sectionName, ":")
 
 			if len(v) == 0 {
 				continue
 			}
 
-			k := readField(sectionName, 0, false)
-
 			section.Fields = append(section.Fields, k)
 			section.Values[k] = v
 
@@ -319,6 +527,20 @@ func parseDBInfo(info string) *DBInfo {
 	return &DBInfo{Keys: kv, Expires: ev, AvgTTL: tv}
 }
 
+// readNamedField returns value of field with given name from comma-separated
+// list of key=value pairs
+func readNamedField(data, name string) string {
+	for _, pair := range strings.Split(data, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+
+		if ok && k == name {
+			return v
+		}
+	}
+
+	return ""
+}
+
 // codebeat:disable[CYCLO]
 
 func readField(data string, index int, multiSep bool, separators ...string) string {
@@ -0,0 +1,283 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Sorting orders
+const (
+	// ORDER_ASC sorts elements from small to large
+	ORDER_ASC = "ASC"
+
+	// ORDER_DESC sorts elements from large to small
+	ORDER_DESC = "DESC"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// CopyOptions contains options for COPY command
+type CopyOptions struct {
+	// DB is index of destination database (used only if ToDB is true)
+	DB int
+
+	// ToDB enables copying to database with index DB instead of current one
+	ToDB bool
+
+	// Replace removes destination key before copying
+	Replace bool
+}
+
+// ScanOptions contains options for SCAN command
+type ScanOptions struct {
+	// Match is glob-style pattern for keys
+	Match string
+
+	// Count is amount of work done at every call
+	Count int
+
+	// Type is type of keys (e.g. string or hash, Redis 6+)
+	Type string
+}
+
+// SortOptions contains options for SORT command
+type SortOptions struct {
+	// By is pattern of external keys used as weights for sorting (use "nosort"
+	// for skipping sorting)
+	By string
+
+	// Offset is number of elements to skip
+	Offset int
+
+	// Count is maximum number of returned elements (0 = all)
+	Count int
+
+	// Get is list of patterns of external keys whose values are returned instead
+	// of elements ("#" means element itself)
+	Get []string
+
+	// Order is sorting order (ORDER_ASC or ORDER_DESC)
+	Order string
+
+	// Alpha enables lexicographical sorting instead of numeric
+	Alpha bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Sort returns sorted elements of the list, set or sorted set stored at key
+func (c *Client) Sort(key string, opts SortOptions) ([]string, error) {
+	return c.Cmd("SORT", key, opts.args()).List()
+}
+
+// SortRO is read-only variant of Sort which can be used on replicas (Redis 7+)
+func (c *Client) SortRO(key string, opts SortOptions) ([]string, error) {
+	return c.Cmd("SORT_RO", key, opts.args()).List()
+}
+
+// SortStore sorts elements of the list, set or sorted set stored at key, stores
+// result as a list at dst key and returns number of elements in the result
+func (c *Client) SortStore(key, dst string, opts SortOptions) (int64, error) {
+	return c.Cmd("SORT", key, opts.args(), "STORE", dst).Int64()
+}
+
+// Scan iterates over keys in current database. Iteration starts with cursor "0"
+// and is finished when returned cursor is "0".
+func (c *Client) Scan(cursor string, opts ScanOptions) (string, []string, error) {
+	return parseScanResp(c.Cmd("SCAN", cursor, opts.args()))
+}
+
+// Copy copies the value stored at src key to dst key (Redis 6.2+). Returns
+// false if value wasn't copied (e.g. destination key already exists).
+func (c *Client) Copy(src, dst string, opts CopyOptions) (bool, error) {
+	return cmdBool(c.Cmd("COPY", src, dst, opts.args()))
+}
+
+// Rename renames src key to dst. Returns error if src key doesn't exist.
+func (c *Client) Rename(src, dst string) error {
+	return okToErr(c.Cmd("RENAME", src, dst))
+}
+
+// RenameNX renames src key to dst if dst key doesn't exist. Returns false if
+// dst key already exists.
+func (c *Client) RenameNX(src, dst string) (bool, error) {
+	return cmdBool(c.Cmd("RENAMENX", src, dst))
+}
+
+// Expire sets timeout on key. If timeout contains fractions of second, PEXPIRE
+// is used instead of EXPIRE. Optional update condition (COND_NX, COND_XX, COND_GT
+// or COND_LT) is supported only by Redis 7+. Returns false if key doesn't exist
+// or timeout wasn't set due to condition.
+func (c *Client) Expire(key string, d time.Duration, cond string) (bool, error) {
+	if d%time.Second != 0 {
+		return c.PExpire(key, d, cond)
+	}
+
+	return cmdBool(c.Cmd("EXPIRE", key, int64(d/time.Second), condArgs(cond)))
+}
+
+// PExpire sets timeout on key with milliseconds precision. Optional update
+// condition (COND_NX, COND_XX, COND_GT or COND_LT) is supported only by Redis 7+.
+// Returns false if key doesn't exist or timeout wasn't set due to condition.
+func (c *Client) PExpire(key string, d time.Duration, cond string) (bool, error) {
+	return cmdBool(c.Cmd("PEXPIRE", key, d.Milliseconds(), condArgs(cond)))
+}
+
+// ExpireAt sets expiration date of key. If date contains fractions of second,
+// PEXPIREAT is used instead of EXPIREAT. Optional update condition (COND_NX,
+// COND_XX, COND_GT or COND_LT) is supported only by Redis 7+. Returns false if
+// key doesn't exist or timeout wasn't set due to condition.
+func (c *Client) ExpireAt(key string, t time.Time, cond string) (bool, error) {
+	if t.Nanosecond() != 0 {
+		return cmdBool(c.Cmd("PEXPIREAT", key, t.UnixMilli(), condArgs(cond)))
+	}
+
+	return cmdBool(c.Cmd("EXPIREAT", key, t.Unix(), condArgs(cond)))
+}
+
+// Persist removes timeout of key. Returns false if key doesn't exist or doesn't
+// have timeout.
+func (c *Client) Persist(key string) (bool, error) {
+	return cmdBool(c.Cmd("PERSIST", key))
+}
+
+// ObjectEncoding returns internal encoding of the value stored at key (e.g.
+// listpack or hashtable). Returns ErrRespNil if key doesn't exist.
+func (c *Client) ObjectEncoding(key string) (string, error) {
+	resp := c.Cmd("OBJECT", "ENCODING", key)
+
+	if resp.HasType(NIL) {
+		return "", ErrRespNil
+	}
+
+	return resp.Str()
+}
+
+// EncodingHistogram scans up to sample keys (all keys if sample is 0) matching
+// given glob-style pattern and returns number of keys per internal encoding.
+// Keys removed during scanning are skipped.
+func (c *Client) EncodingHistogram(pattern string, sample int) (map[string]int, error) {
+	var scanned int
+
+	cursor := "0"
+	result := make(map[string]int)
+
+	for {
+		next, keys, err := c.Scan(cursor, ScanOptions{Match: pattern})
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			if sample > 0 && scanned >= sample {
+				return result, nil
+			}
+
+			encoding, err := c.ObjectEncoding(key)
+
+			switch err {
+			case nil:
+				result[encoding]++
+				scanned++
+			case ErrRespNil:
+				continue
+			default:
+				return nil, err
+			}
+		}
+
+		if next == "0" {
+			return result, nil
+		}
+
+		cursor = next
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// args returns COPY arguments for options
+func (o CopyOptions) args() []any {
+	var args []any
+
+	if o.ToDB {
+		args = append(args, "DB", o.DB)
+	}
+
+	if o.Replace {
+		args = append(args, "REPLACE")
+	}
+
+	return args
+}
+
+// args returns SCAN arguments for options
+func (o ScanOptions) args() []any {
+	var args []any
+
+	if o.Match != "" {
+		args = append(args, "MATCH", o.Match)
+	}
+
+	if o.Count > 0 {
+		args = append(args, "COUNT", o.Count)
+	}
+
+	if o.Type != "" {
+		args = append(args, "TYPE", o.Type)
+	}
+
+	return args
+}
+
+// args returns SORT arguments for options
+func (o SortOptions) args() []any {
+	var args []any
+
+	if o.By != "" {
+		args = append(args, "BY", o.By)
+	}
+
+	if o.Offset > 0 || o.Count > 0 {
+		count := o.Count
+
+		if count <= 0 {
+			count = -1
+		}
+
+		args = append(args, "LIMIT", o.Offset, count)
+	}
+
+	for _, pattern := range o.Get {
+		args = append(args, "GET", pattern)
+	}
+
+	if o.Order != "" {
+		args = append(args, o.Order)
+	}
+
+	if o.Alpha {
+		args = append(args, "ALPHA")
+	}
+
+	return args
+}
+
+// condArgs returns arguments for optional update condition
+func condArgs(cond string) []string {
+	if cond == "" {
+		return nil
+	}
+
+	return []string{cond}
+}
+
+// cmdBool converts integer reply of command (1 or 0) to boolean
+func cmdBool(r *Resp) (bool, error) {
+	v, err := r.Int()
+	return v == 1, err
+}
@@ -0,0 +1,124 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// LatencyEvent contains info about latest latency spike for event
+type LatencyEvent struct {
+	Event  string
+	Time   time.Time
+	Latest time.Duration
+	Max    time.Duration
+}
+
+// LatencySample contains info about single latency spike
+type LatencySample struct {
+	Time    time.Time
+	Latency time.Duration
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var ErrWrongLatencyResponse = errors.New("LATENCY command response must have Array type")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ParseLatencyLatest parses LATENCY LATEST output
+func ParseLatencyLatest(r *Resp) ([]LatencyEvent, error) {
+	items, err := getLatencyItems(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]LatencyEvent, 0, len(items))
+
+	for _, item := range items {
+		fields, err := item.Array()
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse LATENCY data: %v", err)
+		}
+
+		if len(fields) < 4 {
+			return nil, errors.New("Can't parse LATENCY data: not enough fields")
+		}
+
+		event, err := fields[0].Str()
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse LATENCY data: %v", err)
+		}
+
+		ts, err1 := fields[1].Int64()
+		latest, err2 := fields[2].Int64()
+		max, err3 := fields[3].Int64()
+
+		if err1 != nil || err2 != nil || err3 != nil {
+			return nil, errors.New("Can't parse LATENCY data: wrong value type")
+		}
+
+		result = append(result, LatencyEvent{
+			Event:  event,
+			Time:   time.Unix(ts, 0),
+			Latest: time.Duration(latest) * time.Millisecond,
+			Max:    time.Duration(max) * time.Millisecond,
+		})
+	}
+
+	return result, nil
+}
+
+// ParseLatencyHistory parses LATENCY HISTORY output
+func ParseLatencyHistory(r *Resp) ([]LatencySample, error) {
+	items, err := getLatencyItems(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]LatencySample, 0, len(items))
+
+	for _, item := range items {
+		fields, err := item.Array()
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse LATENCY data: %v", err)
+		}
+
+		if len(fields) < 2 {
+			return nil, errors.New("Can't parse LATENCY data: not enough fields")
+		}
+
+		ts, err1 := fields[0].Int64()
+		latency, err2 := fields[1].Int64()
+
+		if err1 != nil || err2 != nil {
+			return nil, errors.New("Can't parse LATENCY data: wrong value type")
+		}
+
+		result = append(result, LatencySample{
+			Time:    time.Unix(ts, 0),
+			Latency: time.Duration(latency) * time.Millisecond,
+		})
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func getLatencyItems(r *Resp) ([]*Resp, error) {
+	if !r.HasType(ARRAY) {
+		return nil, ErrWrongLatencyResponse
+	}
+
+	return r.Array()
+}
@@ -0,0 +1,97 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// LPosOptions contains options for LPOS command
+type LPosOptions struct {
+	// Rank is rank of the first match to return (negative rank means searching
+	// from the tail of the list)
+	Rank int
+
+	// Count is maximum number of returned positions, zero means all matches
+	// (used only if WithCount is true)
+	Count int
+
+	// WithCount enables returning multiple positions using COUNT option
+	WithCount bool
+
+	// MaxLen is maximum number of compared elements
+	MaxLen int
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// LPush inserts all given values at the head of the list stored at key and
+// returns length of the list after operation. Values can be of any type
+// supported by Cmd (strings, byte slices, numbers, slices of them).
+func (c *Client) LPush(key string, values ...any) (int64, error) {
+	return c.Cmd("LPUSH", key, values).Int64()
+}
+
+// RPush inserts all given values at the tail of the list stored at key and
+// returns length of the list after operation
+func (c *Client) RPush(key string, values ...any) (int64, error) {
+	return c.Cmd("RPUSH", key, values).Int64()
+}
+
+// LRange returns elements of the list stored at key between start and stop
+// offsets (inclusive). Negative offsets are counted from the end of the list.
+func (c *Client) LRange(key string, start, stop int64) ([]string, error) {
+	return c.Cmd("LRANGE", key, start, stop).List()
+}
+
+// LPos returns positions of elements matching given element in the list stored
+// at key (Redis 6.0.6+). Without WithCount option at most one position is
+// returned. Empty slice is returned if there are no matches.
+func (c *Client) LPos(key string, element any, opts LPosOptions) ([]int64, error) {
+	r := c.Cmd("LPOS", key, element, opts.args())
+
+	switch {
+	case r.Err != nil:
+		return nil, r.Err
+	case r.HasType(NIL):
+		return []int64{}, nil
+	case r.HasType(INT):
+		pos, _ := r.Int64()
+		return []int64{pos}, nil
+	}
+
+	items, err := r.Array()
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]int64, len(items))
+
+	for i, item := range items {
+		result[i], err = item.Int64()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// args returns LPOS arguments for options
+func (o LPosOptions) args() []any {
+	var args []any
+
+	if o.Rank != 0 {
+		args = append(args, "RANK", o.Rank)
+	}
+
+	if o.WithCount {
+		args = append(args, "COUNT", o.Count)
+	}
+
+	if o.MaxLen > 0 {
+		args = append(args, "MAXLEN", o.MaxLen)
+	}
+
+	return args
+}
@@ -0,0 +1,154 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"strings"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// KeyspaceWatcher is keyspace notifications consumer
+type KeyspaceWatcher struct {
+	// AutoReconnect enables reconnecting and re-subscribing if connection was
+	// closed due to error
+	AutoReconnect bool
+
+	client  *Client
+	pattern string
+	stopped bool
+}
+
+// KeyEvent contains info about keyspace event
+type KeyEvent struct {
+	Key   string
+	Event string
+
+	// Reconnected is true for special event which is returned after reconnect.
+	// Some events could be missed while client was disconnected.
+	Reconnected bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ErrKeyspaceNotifyDisabled is returned if keyspace notifications are disabled
+var ErrKeyspaceNotifyDisabled = errors.New("Keyspace notifications are disabled (notify-keyspace-events must contain 'K' flag)")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const keyspacePrefix = "__keyspace@"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// WatchKeyspace subscribes to keyspace notifications for keys matching given
+// glob-style pattern in all databases. After this call the connection is in
+// subscribed state and can be used only for reading events, so use a dedicated
+// client for watching. Config command name is used for checking that keyspace
+// notifications are enabled.
+func (c *Client) WatchKeyspace(configCommand, pattern string) (*KeyspaceWatcher, error) {
+	conf, err := c.GetConfigMatching(configCommand, "notify-keyspace-events")
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.Contains(conf.Get("notify-keyspace-events"), "K") {
+		return nil, ErrKeyspaceNotifyDisabled
+	}
+
+	if pattern == "" {
+		pattern = "*"
+	}
+
+	w := &KeyspaceWatcher{client: c, pattern: pattern}
+	err = w.subscribe()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Next waits for the next keyspace event. If client has ReadTimeout, error
+// is returned if there were no events during timeout, and the method can be
+// called again. If AutoReconnect is enabled and connection was closed due to
+// error, watcher reconnects, re-subscribes and returns event with Reconnected
+// flag. Reconnect error is returned if reconnect failed, and the method can be
+// called again for another attempt.
+func (w *KeyspaceWatcher) Next() (*KeyEvent, error) {
+	for {
+		if w.AutoReconnect && !w.stopped && !w.client.isHealthy() {
+			err := w.resubscribe()
+
+			if err != nil {
+				return nil, err
+			}
+
+			return &KeyEvent{Reconnected: true}, nil
+		}
+
+		if w.client.conn == nil {
+			return nil, ErrNotConnected
+		}
+
+		resp := w.client.readResp(false)
+
+		if resp.Err != nil {
+			if w.AutoReconnect && !w.stopped && !w.client.isHealthy() {
+				continue
+			}
+
+			return nil, resp.Err
+		}
+
+		items, err := resp.Array()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(items) != 4 {
+			continue // skip subscription confirmations
+		}
+
+		kind, _ := items[0].Str()
+		channel, _ := items[2].Str()
+		event, _ := items[3].Str()
+
+		_, key, ok := strings.Cut(channel, "__:")
+
+		if kind != "pmessage" || !ok {
+			continue
+		}
+
+		return &KeyEvent{Key: key, Event: event}, nil
+	}
+}
+
+// Close stops watching and closes client connection
+func (w *KeyspaceWatcher) Close() error {
+	w.stopped = true
+	return w.client.Close()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// subscribe subscribes client to keyspace notifications
+func (w *KeyspaceWatcher) subscribe() error {
+	return w.client.writeRequest(req{"PSUBSCRIBE", []any{keyspacePrefix + "*__:" + w.pattern}})
+}
+
+// resubscribe reconnects client and subscribes it to keyspace notifications
+func (w *KeyspaceWatcher) resubscribe() error {
+	err := w.client.Connect()
+
+	if err != nil {
+		return err
+	}
+
+	return w.subscribe()
+}
@@ -0,0 +1,90 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Pipeline is isolated queue of commands which will be sent to Redis in one batch
+type Pipeline struct {
+	client  *Client
+	pending []req
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Pipeline creates new isolated pipeline bound to client connection
+func (c *Client) Pipeline() *Pipeline {
+	return &Pipeline{client: c}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Append adds the given call to the pipeline queue
+func (p *Pipeline) Append(cmd string, args ...any) {
+	p.pending = append(p.pending, req{cmd, args})
+}
+
+// Len returns number of commands in the pipeline queue
+func (p *Pipeline) Len() int {
+	return len(p.pending)
+}
+
+// Clear removes all commands from the pipeline queue
+func (p *Pipeline) Clear() {
+	p.pending = nil
+}
+
+// Exec sends all queued commands to Redis and returns replies in the same order.
+// Queue is cleared after the call even if error occurred. If connection error
+// occurs while reading replies, the replies read so far are returned with
+// the error. Reading of all replies is limited by client PipeTimeout.
+func (p *Pipeline) Exec() ([]*Resp, error) {
+	c := p.client
+
+	if c == nil || c.conn == nil {
+		return nil, ErrNotConnected
+	}
+
+	if len(p.pending) == 0 {
+		return nil, ErrEmptyPipeline
+	}
+
+	pending := p.pending
+	p.pending = nil
+
+	err := c.writeRequest(pending...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c.PipeTimeout > 0 {
+		c.batchDeadline = getDeadline(c.PipeTimeout)
+	}
+
+	result := make([]*Resp, 0, len(pending))
+
+	for range pending {
+		resp := c.readReply()
+		result = append(result, resp)
+
+		if resp.HasType(ERR_IO) {
+			err = resp.Err
+			break
+		}
+	}
+
+	if !c.batchDeadline.IsZero() {
+		c.batchDeadline = time.Time{}
+
+		if c.ReadTimeout <= 0 && c.conn != nil {
+			c.conn.SetReadDeadline(time.Time{})
+		}
+	}
+
+	return result, err
+}
@@ -5,12 +5,18 @@ package redy
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
+	"io"
+	"math"
 	"math/rand"
 	"net"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +29,19 @@ type timeoutError struct{}
 
 type errReader struct{}
 
+type errWriter struct{}
+
+type deadlineConn struct {
+	net.Conn
+	readDeadline  bool
+	writeDeadline bool
+}
+
+type cancelConn struct {
+	net.Conn
+	cancel context.CancelFunc
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 func Test(t *testing.T) { TestingT(t) }
@@ -67,9 +86,10 @@ func (rs *RedySuite) SetUpSuite(c *C) {
 
 func (rs *RedySuite) TestConnectionError(c *C) {
 	rc := &Client{
-		Network:   "tcp",
-		Addr:      "127.0.0.255:60000",
-		TLSConfig: &tls.Config{},
+		Network:     "tcp",
+		Addr:        "127.0.0.255:60000",
+		TLSConfig:   &tls.Config{},
+		DialTimeout: time.Second,
 	}
 
 	resp := rc.Cmd("PING")
@@ -80,6 +100,252 @@ func (rs *RedySuite) TestConnectionError(c *C) {
 
 	err := rc.Connect()
 	c.Assert(err, NotNil)
+
+	conn, err := rc.dial()
+	c.Assert(err, NotNil)
+	c.Assert(conn == nil, Equals, true)
+
+	rc.DialFunc = func(network, addr string) (net.Conn, error) {
+		conn, _ := net.Pipe()
+		return conn, errors.New("Dial error")
+	}
+
+	conn, err = rc.dial()
+	c.Assert(err, NotNil)
+	c.Assert(conn == nil, Equals, true)
+}
+
+func (rs *RedySuite) TestTLSDialTimeout(c *C) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+
+	defer l.Close()
+
+	go func() {
+		// accept connections and never respond
+		for {
+			_, err := l.Accept()
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	rc := &Client{
+		Addr:        l.Addr().String(),
+		TLSConfig:   &tls.Config{},
+		DialTimeout: 50 * time.Millisecond,
+	}
+
+	start := time.Now()
+
+	c.Assert(rc.Connect(), NotNil)
+	c.Assert(time.Since(start) < 5*time.Second, Equals, true)
+
+	rc.DialFunc = func(network, addr string) (net.Conn, error) {
+		return net.Dial(network, addr)
+	}
+
+	start = time.Now()
+
+	c.Assert(rc.Connect(), NotNil)
+	c.Assert(time.Since(start) < 5*time.Second, Equals, true)
+}
+
+func (rs *RedySuite) TestTLSConfigFunc(c *C) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+
+	defer l.Close()
+
+	go func() {
+		// accept connections and never respond
+		for {
+			_, err := l.Accept()
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var configAddr string
+	var config *tls.Config
+
+	rc := &Client{
+		Addr:        l.Addr().String(),
+		DialTimeout: 50 * time.Millisecond,
+		TLSConfigFunc: func(addr string) *tls.Config {
+			configAddr = addr
+			return config
+		},
+	}
+
+	c.Assert(rc.Connect(), IsNil)
+	c.Assert(rc.IsTLS(), Equals, false)
+	c.Assert(configAddr, Equals, l.Addr().String())
+
+	rc.Close()
+
+	config = &tls.Config{}
+
+	c.Assert(rc.Connect(), NotNil)
+}
+
+func (rs *RedySuite) TestDialFunc(c *C) {
+	var dialed bool
+
+	rc := &Client{
+		Addr: rs.c.Addr,
+		DialFunc: func(network, addr string) (net.Conn, error) {
+			dialed = true
+			return net.Dial(network, addr)
+		},
+	}
+
+	err := rc.Connect()
+	c.Assert(err, IsNil)
+	c.Assert(dialed, Equals, true)
+
+	val, err := rc.Cmd("ECHO", "TEST").Str()
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "TEST")
+
+	rc.Close()
+
+	rc.DialFunc = func(network, addr string) (net.Conn, error) {
+		return nil, errors.New("Dial error")
+	}
+
+	c.Assert(rc.Connect(), NotNil)
+
+	rc = &Client{Addr: "127.0.0.1:6379", TLSConfig: &tls.Config{}}
+	c.Assert(rc.getTLSConfig(rc.TLSConfig).ServerName, Equals, "127.0.0.1")
+	c.Assert(rc.TLSConfig.ServerName, Equals, "")
+
+	rc = &Client{Addr: "localhost", TLSConfig: &tls.Config{ServerName: "redis.local"}}
+	c.Assert(rc.getTLSConfig(rc.TLSConfig).ServerName, Equals, "redis.local")
+
+	rc = &Client{Addr: "localhost", TLSConfig: &tls.Config{}}
+	c.Assert(rc.getTLSConfig(rc.TLSConfig).ServerName, Equals, "localhost")
+}
+
+func (rs *RedySuite) TestUnixClient(c *C) {
+	rc := NewUnixClient("/tmp/_redy_unknown.sock")
+
+	c.Assert(rc.Network, Equals, "unix")
+	c.Assert(rc.Addr, Equals, "/tmp/_redy_unknown.sock")
+	c.Assert(rc.isUnixSocket(), Equals, true)
+	c.Assert(rc.Connect(), NotNil)
+
+	rc.TLSConfig = &tls.Config{}
+	c.Assert(rc.getTLSConfig(rc.TLSConfig).ServerName, Equals, "")
+	c.Assert(rc.Connect(), NotNil)
+
+	c.Assert(rs.c.isUnixSocket(), Equals, false)
+}
+
+func (rs *RedySuite) TestConnectRetry(c *C) {
+	var attempts int
+
+	rc := &Client{
+		Addr: rs.c.Addr,
+		DialFunc: func(network, addr string) (net.Conn, error) {
+			attempts++
+
+			if attempts < 3 {
+				return nil, errors.New("Dial error")
+			}
+
+			return net.Dial(network, addr)
+		},
+	}
+
+	start := time.Now()
+
+	c.Assert(rc.ConnectRetry(5, 5*time.Millisecond), IsNil)
+	c.Assert(attempts, Equals, 3)
+	c.Assert(time.Since(start) >= 15*time.Millisecond, Equals, true)
+
+	rc.Close()
+
+	attempts = -10
+
+	c.Assert(rc.ConnectRetry(3, time.Millisecond), NotNil)
+	c.Assert(attempts, Equals, -7)
+
+	attempts = -10
+
+	c.Assert(rc.ConnectRetry(0, time.Millisecond), NotNil)
+	c.Assert(attempts, Equals, -9)
+}
+
+func (rs *RedySuite) TestConnMetadata(c *C) {
+	rc := &Client{Addr: rs.c.Addr}
+
+	c.Assert(rc.LocalAddr(), IsNil)
+	c.Assert(rc.RemoteAddr(), IsNil)
+	c.Assert(rc.IsTLS(), Equals, false)
+
+	err := rc.Connect()
+	c.Assert(err, IsNil)
+
+	c.Assert(rc.LocalAddr(), NotNil)
+	c.Assert(rc.RemoteAddr(), NotNil)
+	c.Assert(rc.RemoteAddr().String(), Equals, rs.c.Addr)
+	c.Assert(rc.IsTLS(), Equals, false)
+
+	rc.Close()
+
+	rc = &Client{conn: tls.Client(&net.TCPConn{}, &tls.Config{})}
+	c.Assert(rc.IsTLS(), Equals, true)
+}
+
+func (rs *RedySuite) TestOnReconnect(c *C) {
+	var attempts []int
+	var errs int
+	var fail bool
+
+	rc := &Client{
+		Addr: rs.c.Addr,
+		DialFunc: func(network, addr string) (net.Conn, error) {
+			if fail {
+				return nil, errors.New("Dial error")
+			}
+
+			return net.Dial(network, addr)
+		},
+		OnReconnect: func(attempt int, err error) {
+			attempts = append(attempts, attempt)
+
+			if err != nil {
+				errs++
+			}
+		},
+	}
+
+	c.Assert(rc.Connect(), IsNil)
+	c.Assert(attempts, HasLen, 0)
+	c.Assert(rc.Reconnects, Equals, int64(0))
+
+	rc.Close()
+
+	fail = true
+
+	c.Assert(rc.ConnectRetry(2, time.Millisecond), NotNil)
+	c.Assert(attempts, DeepEquals, []int{1, 2})
+	c.Assert(errs, Equals, 2)
+	c.Assert(rc.Reconnects, Equals, int64(0))
+
+	fail = false
+
+	c.Assert(rc.Connect(), IsNil)
+	c.Assert(attempts, DeepEquals, []int{1, 2, 1})
+	c.Assert(errs, Equals, 2)
+	c.Assert(rc.Reconnects, Equals, int64(1))
+
+	rc.Close()
 }
 
 func (rs *RedySuite) TestCmd(c *C) {
@@ -126,6 +392,22 @@ func (rs *RedySuite) TestCmd(c *C) {
 	c.Assert(respList, DeepEquals, []string{"blah", "foo", "10", "0"})
 }
 
+func (rs *RedySuite) TestDo(c *C) {
+	s, err := Do(rs.c, (*Resp).Str, "ECHO", "TEST1234")
+	c.Assert(err, IsNil)
+	c.Assert(s, Equals, "TEST1234")
+
+	i, err := Do(rs.c, (*Resp).Int64, "ECHO", 1024)
+	c.Assert(err, IsNil)
+	c.Assert(i, Equals, int64(1024))
+
+	_, err = Do(rs.c, (*Resp).Int, "ECHO", "TEST")
+	c.Assert(err, NotNil)
+
+	_, err = Do(&Client{}, (*Resp).Str, "ECHO", "TEST")
+	c.Assert(err, Equals, ErrNotConnected)
+}
+
 func (rs *RedySuite) TestPipeline(c *C) {
 	// Do this multiple times to make sure pipeline resetting happens correctly
 	for i := 0; i < 3; i++ {
@@ -170,154 +452,2026 @@ func (rs *RedySuite) TestPipeline(c *C) {
 	c.Assert(complete, Equals, 1)
 }
 
-func (rs *RedySuite) TestReconnect(c *C) {
-	rs.c.Close()
-	err := rs.c.Connect()
+func (rs *RedySuite) TestPipelineObject(c *C) {
+	p1 := rs.c.Pipeline()
+	p2 := rs.c.Pipeline()
 
-	c.Assert(err, IsNil)
-}
+	_, err := p1.Exec()
+	c.Assert(err, Equals, ErrEmptyPipeline)
 
-func (rs *RedySuite) TestLastCritical(c *C) {
-	rc := &Client{
-		Addr: rs.c.Addr,
-	}
+	p1.Append("ECHO", "foo")
+	p1.Append("ECHO", "bar")
+	p2.Append("ECHO", "zot")
 
-	err := rc.Connect()
+	c.Assert(p1.Len(), Equals, 2)
+	c.Assert(p2.Len(), Equals, 1)
+
+	r := rs.c.Cmd("ECHO", "test")
+	c.Assert(r.Err, IsNil)
+
+	resps, err := p2.Exec()
+	c.Assert(err, IsNil)
+	c.Assert(resps, HasLen, 1)
+	val, err := resps[0].Str()
 	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "zot")
+	c.Assert(p2.Len(), Equals, 0)
 
-	err = rc.Cmd("UNKNOWN_COMMAND").Err
-	c.Assert(err, NotNil)
-	c.Assert(rc.LastCritical, IsNil)
+	resps, err = p1.Exec()
+	c.Assert(err, IsNil)
+	c.Assert(resps, HasLen, 2)
+	val, err = resps[0].Str()
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "foo")
+	val, err = resps[1].Str()
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "bar")
 
-	rc.Close()
+	p1.Append("ECHO", "foo")
+	p1.Clear()
+	c.Assert(p1.Len(), Equals, 0)
 
-	err = rc.Cmd("UNKNOWN_COMMAND").Err
-	c.Assert(err, NotNil)
-	c.Assert(rc.LastCritical, NotNil)
+	rc := &Client{}
+	p3 := rc.Pipeline()
+	p3.Append("ECHO", "foo")
+	_, err = p3.Exec()
+	c.Assert(err, Equals, ErrNotConnected)
 }
 
-func (rs *RedySuite) TestRespRead(c *C) {
-	var r *Resp
-	var err error
-
-	r = pretendRead("")
-	c.Assert(r.Err, NotNil)
+func (rs *RedySuite) TestBitCommands(c *C) {
+	key := randString(12)
 
-	// Simple string
-	r = pretendRead("+TEST1234\r\n")
-	c.Assert(r.HasType(STR_SIMPLE), Equals, true)
-	c.Assert(r.val, DeepEquals, []byte("TEST1234"))
-	s, err := r.Str()
+	v, err := rs.c.SetBit(key, 7, 1)
 	c.Assert(err, IsNil)
-	c.Assert(s, Equals, "TEST1234")
-	c.Assert(r.String(), Equals, "Resp(Str \"TEST1234\")")
+	c.Assert(v, Equals, 0)
 
-	// Empty simple string
-	r = pretendRead("+\r\n")
-	c.Assert(r.HasType(STR_SIMPLE), Equals, true)
-	c.Assert(r.val, DeepEquals, []byte(""))
-	s, err = r.Str()
+	v, err = rs.c.SetBit(key, 9, 1)
 	c.Assert(err, IsNil)
-	c.Assert(s, Equals, "")
-	c.Assert(r.String(), Equals, "Resp(Str \"\")")
-
-	// Error
-	r = pretendRead("-TEST1234\r\n")
-	c.Assert(r.HasType(ERR_REDIS), Equals, true)
-	c.Assert(r.val, DeepEquals, errors.New("TEST1234"))
-	c.Assert(r.Err.Error(), Equals, "TEST1234")
-	c.Assert(r.String(), Equals, "Resp(RedisErr \"TEST1234\")")
-
-	// Empty error
-	r = pretendRead("-\r\n")
-	c.Assert(r.HasType(ERR_REDIS), Equals, true)
-	c.Assert(r.val, DeepEquals, errors.New(""))
-	c.Assert(r.Err.Error(), Equals, "")
-	c.Assert(r.String(), Equals, "Resp(RedisErr \"\")")
+	c.Assert(v, Equals, 0)
 
-	// Int
-	r = pretendRead(":1024\r\n")
-	c.Assert(r.HasType(INT), Equals, true)
-	c.Assert(r.val, Equals, int64(1024))
-	i, err := r.Int()
+	v, err = rs.c.GetBit(key, 7)
 	c.Assert(err, IsNil)
-	c.Assert(i, Equals, 1024)
-	c.Assert(r.String(), Equals, "Resp(Int 1024)")
+	c.Assert(v, Equals, 1)
 
-	// Check int max
-	maxInt = 10
-	i, err = r.Int()
+	v, err = rs.c.GetBit(key, 8)
 	c.Assert(err, IsNil)
-	c.Assert(i, Equals, 10)
-	maxInt = int(^uint(0) >> 1)
+	c.Assert(v, Equals, 0)
 
-	// Int (from string)
-	r = pretendRead("+50\r\n")
-	c.Assert(r.HasType(STR_SIMPLE), Equals, true)
-	c.Assert(r.val, DeepEquals, []byte("50"))
-	i, err = r.Int()
+	n, err := rs.c.BitCount(key, 0, -1, BIT_UNIT_DEFAULT)
 	c.Assert(err, IsNil)
-	c.Assert(i, Equals, 50)
-	i64, err := r.Int64()
+	c.Assert(n, Equals, int64(2))
+
+	n, err = rs.c.BitCount(key, 1, 1, BIT_UNIT_BYTE)
 	c.Assert(err, IsNil)
-	c.Assert(i64, Equals, int64(50))
-	f, err := r.Float64()
+	c.Assert(n, Equals, int64(1))
+
+	n, err = rs.c.BitPos(key, 1, 0, -1, BIT_UNIT_DEFAULT)
 	c.Assert(err, IsNil)
-	c.Assert(f, Equals, 50.0)
-	c.Assert(r.String(), Equals, "Resp(Str \"50\")")
+	c.Assert(n, Equals, int64(7))
 
-	// Int (from string, can't parse)
-	r = pretendRead("+TEST1234\r\n")
-	c.Assert(r.HasType(STR_SIMPLE), Equals, true)
-	c.Assert(r.val, DeepEquals, []byte("TEST1234"))
-	_, err = r.Int()
-	c.Assert(err, NotNil)
-	c.Assert(r.String(), Equals, "Resp(Str \"TEST1234\")")
+	c.Assert(BIT_UNIT_DEFAULT.args(), IsNil)
+	c.Assert(BIT_UNIT_BYTE.args(), DeepEquals, []any{"BYTE"})
+	c.Assert(BIT_UNIT_BIT.args(), DeepEquals, []any{"BIT"})
+}
 
-	// Bulk string
-	r = pretendRead("$8\r\nTEST1234\r\n")
-	c.Assert(r.HasType(STR_BULK), Equals, true)
-	c.Assert(r.val, DeepEquals, []byte("TEST1234"))
-	s, err = r.Str()
+func (rs *RedySuite) TestHGetAllScan(c *C) {
+	key := randString(12)
+	data := make(map[string]string)
+
+	for i := 0; i < 300; i++ {
+		data[randString(8)] = randString(16)
+	}
+
+	r := rs.c.Cmd("HSET", key, data)
+	c.Assert(r.Err, IsNil)
+
+	m, err := rs.c.HGetAllScan(key, 50)
 	c.Assert(err, IsNil)
-	c.Assert(s, Equals, "TEST1234")
-	c.Assert(r.String(), Equals, "Resp(BulkStr \"TEST1234\")")
+	c.Assert(m, DeepEquals, data)
 
-	// Empty bulk string
-	r = pretendRead("$0\r\n\r\n")
-	c.Assert(r.HasType(STR_BULK), Equals, true)
-	c.Assert(r.val, DeepEquals, []byte(""))
-	s, err = r.Str()
+	m, err = rs.c.HGetAllScan(randString(12), 0)
 	c.Assert(err, IsNil)
-	c.Assert(s, Equals, "")
-	c.Assert(r.String(), Equals, "Resp(BulkStr \"\")")
+	c.Assert(m, HasLen, 0)
 
-	// Nil bulk string
-	r = pretendRead("$-1\r\n")
-	c.Assert(r.HasType(NIL), Equals, true)
-	c.Assert(r.String(), Equals, "Resp(Nil)")
+	rs.c.Cmd("SET", key, "test")
 
-	// Array
-	r = pretendRead("*2\r\n+TEST\r\n+1234\r\n")
-	c.Assert(r.HasType(ARRAY), Equals, true)
-	c.Assert(len(r.val.([]Resp)), Equals, 2)
-	c.Assert(r.val.([]Resp)[0].HasType(STR_SIMPLE), Equals, true)
-	c.Assert(r.val.([]Resp)[0].val, DeepEquals, []byte("TEST"))
-	c.Assert(r.val.([]Resp)[1].HasType(STR_SIMPLE), Equals, true)
-	c.Assert(r.val.([]Resp)[1].val, DeepEquals, []byte("1234"))
+	_, err = rs.c.HGetAllScan(key, 10)
+	c.Assert(err, NotNil)
+}
 
-	l, err := r.List()
+func (rs *RedySuite) TestHashFieldTTL(c *C) {
+	rc := newPipeClient(
+		"*2\r\n:1\r\n:-2\r\n*1\r\n:2\r\n*3\r\n:60\r\n:-1\r\n:-2\r\n-ERR\r\n*1\r\n+OK\r\n",
+	)
+
+	codes, err := rc.HExpire("test", time.Minute, "a", "b")
 	c.Assert(err, IsNil)
-	c.Assert(l, DeepEquals, []string{"TEST", "1234"})
+	c.Assert(codes, DeepEquals, []int{1, -2})
 
-	lb, err := r.ListBytes()
+	codes, err = rc.HExpire("test", 1500*time.Millisecond, "a")
 	c.Assert(err, IsNil)
-	c.Assert(lb, DeepEquals, [][]byte{[]byte("TEST"), []byte("1234")})
+	c.Assert(codes, DeepEquals, []int{2})
 
-	m, err := r.Map()
+	ttls, err := rc.HTTL("test", "a", "b", "c")
 	c.Assert(err, IsNil)
-	c.Assert(m, DeepEquals, map[string]string{"TEST": "1234"})
-	c.Assert(r.String(), Equals, "Resp(0:Resp(Str \"TEST\") 1:Resp(Str \"1234\"))")
+	c.Assert(ttls, DeepEquals, []time.Duration{time.Minute, TTL_NO_EXPIRE, TTL_NOT_EXIST})
+
+	_, err = rc.HTTL("test", "a")
+	c.Assert(err, NotNil)
+	_, err = rc.HExpire("test", time.Minute, "a")
+	c.Assert(err, NotNil)
+
+	rc.Close()
+}
+
+func (rs *RedySuite) TestScanRespParser(c *C) {
+	cursor, items, err := parseScanResp(pretendRead("*2\r\n$2\r\n17\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n"))
+	c.Assert(err, IsNil)
+	c.Assert(cursor, Equals, "17")
+	c.Assert(items, DeepEquals, []string{"a", "b"})
+
+	_, _, err = parseScanResp(pretendRead("+OK\r\n"))
+	c.Assert(err, NotNil)
+	_, _, err = parseScanResp(pretendRead("*1\r\n$1\r\n0\r\n"))
+	c.Assert(err, Equals, ErrWrongScanResponse)
+	_, _, err = parseScanResp(pretendRead("*2\r\n*0\r\n*0\r\n"))
+	c.Assert(err, NotNil)
+	_, _, err = parseScanResp(pretendRead("*2\r\n$1\r\n0\r\n:1\r\n"))
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestClientCommands(c *C) {
+	rc := &Client{Addr: rs.c.Addr}
+
+	err := rc.Connect()
+	c.Assert(err, IsNil)
+
+	id, err := rc.Cmd("CLIENT", "ID").Int64()
+	c.Assert(err, IsNil)
+
+	c.Assert(rs.c.ClientNoEvict(true), IsNil)
+	c.Assert(rs.c.ClientNoEvict(false), IsNil)
+
+	c.Assert(rs.c.ClientPause(100*time.Millisecond, PAUSE_WRITE), IsNil)
+	c.Assert(rs.c.ClientUnpause(), IsNil)
+	c.Assert(rs.c.ClientPause(time.Millisecond, ""), IsNil)
+
+	c.Assert(rs.c.ClientKillByID(id), IsNil)
+	c.Assert(rs.c.ClientKillByID(id), Equals, ErrClientNotFound)
+
+	n, err := rs.c.ClientKillByAddr("127.0.0.255:1")
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(0))
+
+	c.Assert(okToErr(&Resp{typ: STR_SIMPLE, val: []byte("QUEUED")}), Equals, ErrNotOK)
+	c.Assert(okToErr(&Resp{Err: ErrNotConnected}), Equals, ErrNotConnected)
+}
+
+func (rs *RedySuite) TestShutdown(c *C) {
+	rc := &Client{Addr: rs.c.Addr}
+
+	c.Assert(rc.Shutdown(), Equals, ErrNotConnected)
+
+	err := rc.Connect()
+	c.Assert(err, IsNil)
+
+	key := randString(12)
+
+	rc.PipeAppend("SET", key, "test")
+	rc.PipeAppend("EXPIRE", key, 60)
+
+	c.Assert(rc.Shutdown(), IsNil)
+	c.Assert(rc.pending, HasLen, 0)
+	c.Assert(rc.completed, HasLen, 0)
+
+	val, err := rs.c.Cmd("GET", key).Str()
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "test")
+
+	err = rc.Connect()
+	c.Assert(err, IsNil)
+	c.Assert(rc.Shutdown(), IsNil)
+}
+
+func (rs *RedySuite) TestReset(c *C) {
+	var setups int
+
+	rc := &Client{
+		Addr: rs.c.Addr,
+		OnConnect: func(c *Client) error {
+			setups++
+			return c.Cmd("SELECT", 2).Err
+		},
+	}
+
+	c.Assert(rc.Reset(), Equals, ErrNotConnected)
+
+	err := rc.Connect()
+	c.Assert(err, IsNil)
+	c.Assert(setups, Equals, 1)
+
+	rc.PipeAppend("ECHO", "foo")
+
+	c.Assert(rc.Cmd("SELECT", 1).Err, IsNil)
+	c.Assert(rc.Reset(), IsNil)
+	c.Assert(rc.pending, HasLen, 0)
+	c.Assert(setups, Equals, 2)
+
+	db, err := rc.Cmd("CLIENT", "INFO").Str()
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(db, " db=2 "), Equals, true)
+
+	rc.Close()
+
+	rc = newPipeClient("+RESET\r\n")
+	rc.OnConnect = func(c *Client) error { return ErrUnexpectedResp }
+
+	c.Assert(rc.Reset(), Equals, ErrUnexpectedResp)
+
+	rc.Close()
+}
+
+func (rs *RedySuite) TestClientInfo(c *C) {
+	rc := &Client{Addr: rs.c.Addr}
+
+	err := rc.Connect()
+	c.Assert(err, IsNil)
+
+	c.Assert(rc.Cmd("CLIENT", "SETNAME", "redy-test").Err, IsNil)
+	c.Assert(rc.Cmd("SELECT", 2).Err, IsNil)
+
+	info, err := rc.ClientInfo()
+
+	c.Assert(err, IsNil)
+	c.Assert(info, NotNil)
+	c.Assert(info.Name, Equals, "redy-test")
+	c.Assert(info.DB, Equals, 2)
+	c.Assert(info.ID, Not(Equals), int64(0))
+
+	rc.Close()
+
+	_, err = rc.ClientInfo()
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestClientListParser(c *C) {
+	r := pretendRead("$0\r\n\r\n")
+	conns, err := ParseClientList(r)
+
+	c.Assert(err, IsNil)
+	c.Assert(conns, HasLen, 0)
+
+	data := "id=3 addr=127.0.0.1:50188 laddr=127.0.0.1:6379 fd=8 name=test age=12 " +
+		"idle=3 flags=N db=1 sub=2 psub=1 ssub=0 multi=-1 cmd=client|list user=default " +
+		"redir=-1 resp=3 lib-name=redy lib-ver=4.4.0\n" +
+		"id=4 addr=127.0.0.1:50190 laddr=127.0.0.1:6379 fd=9 name= age=0 idle=0 flags=P\n"
+
+	r = &Resp{typ: STR_BULK, val: []byte(data)}
+	conns, err = ParseClientList(r)
+
+	c.Assert(err, IsNil)
+	c.Assert(conns, HasLen, 2)
+
+	conn := conns[0]
+
+	c.Assert(conn.ID, Equals, int64(3))
+	c.Assert(conn.Addr, Equals, "127.0.0.1:50188")
+	c.Assert(conn.LocalAddr, Equals, "127.0.0.1:6379")
+	c.Assert(conn.Name, Equals, "test")
+	c.Assert(conn.Age, Equals, 12*time.Second)
+	c.Assert(conn.Idle, Equals, 3*time.Second)
+	c.Assert(conn.Flags, Equals, "N")
+	c.Assert(conn.DB, Equals, 1)
+	c.Assert(conn.Sub, Equals, 2)
+	c.Assert(conn.PSub, Equals, 1)
+	c.Assert(conn.Multi, Equals, -1)
+	c.Assert(conn.Cmd, Equals, "client|list")
+	c.Assert(conn.User, Equals, "default")
+	c.Assert(conn.Resp, Equals, 3)
+	c.Assert(conn.LibName, Equals, "redy")
+	c.Assert(conn.LibVer, Equals, "4.4.0")
+	c.Assert(conn.Fields["fd"], Equals, "8")
+
+	c.Assert(conns[1].ID, Equals, int64(4))
+	c.Assert(conns[1].Name, Equals, "")
+	c.Assert(conns[1].Flags, Equals, "P")
+
+	_, err = ParseClientList(pretendRead(":1\r\n"))
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestRangeCommands(c *C) {
+	key := randString(12)
+
+	n, err := rs.c.Append(key, "Hello")
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(5))
+
+	n, err = rs.c.Append(key, 123)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(8))
+
+	val, err := rs.c.GetRange(key, 0, 4)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "Hello")
+
+	val, err = rs.c.GetRange(key, -3, -1)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "123")
+
+	n, err = rs.c.SetRange(key, 5, "!")
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(8))
+
+	n, err = rs.c.SetRange(key, 10, "!")
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(11))
+
+	val, err = rs.c.GetRange(key, 0, -1)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "Hello!23\x00\x00!")
+
+	val, err = rs.c.GetRange(randString(12), 0, -1)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "")
+
+	rs.c.Cmd("SADD", key+"_set", "test")
+
+	_, err = rs.c.Append(key+"_set", "test")
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestCounterCommands(c *C) {
+	key := randString(12)
+
+	n, err := rs.c.Incr(key)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(1))
+
+	n, err = rs.c.IncrBy(key, 10)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(11))
+
+	n, err = rs.c.Decr(key)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(10))
+
+	n, err = rs.c.DecrBy(key, 4)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(6))
+
+	f, err := rs.c.IncrByFloat(key, 0.5)
+	c.Assert(err, IsNil)
+	c.Assert(f, Equals, 6.5)
+
+	_, err = rs.c.Incr(key)
+	c.Assert(err, NotNil)
+
+	key = randString(12)
+	rs.c.Cmd("SADD", key, "test")
+
+	_, err = rs.c.Incr(key)
+	c.Assert(err, ErrorMatches, "WRONGTYPE.*")
+}
+
+func (rs *RedySuite) TestSetCommand(c *C) {
+	key := randString(12)
+
+	r, err := rs.c.Set(key, "test1", SetOptions{TTL: time.Minute, Mode: COND_NX})
+	c.Assert(err, IsNil)
+	c.Assert(r.IsOK(), Equals, true)
+
+	r, err = rs.c.Set(key, "test2", SetOptions{Mode: COND_NX})
+	c.Assert(err, IsNil)
+	c.Assert(r.HasType(NIL), Equals, true)
+
+	r, err = rs.c.Set(key, "test3", SetOptions{Mode: COND_XX, KeepTTL: true, Get: true})
+	c.Assert(err, IsNil)
+	val, err := r.Str()
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "test1")
+
+	ttl, err := rs.c.Cmd("TTL", key).Int()
+	c.Assert(err, IsNil)
+	c.Assert(ttl > 0, Equals, true)
+
+	_, err = rs.c.Set(key, "test", SetOptions{Mode: "ABCD"})
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestGetExCommands(c *C) {
+	k := randString(12)
+
+	_, ok, err := rs.c.GetEx(k, GetExOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	c.Assert(rs.c.Cmd("SET", k, "test").Err, IsNil)
+
+	val, ok, err := rs.c.GetEx(k, GetExOptions{TTL: time.Minute})
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+	c.Assert(val, Equals, "test")
+
+	ttl, err := rs.c.Cmd("TTL", k).Int()
+	c.Assert(err, IsNil)
+	c.Assert(ttl > 0, Equals, true)
+
+	_, _, err = rs.c.GetEx(k, GetExOptions{Persist: true})
+	c.Assert(err, IsNil)
+
+	ttl, err = rs.c.Cmd("TTL", k).Int()
+	c.Assert(err, IsNil)
+	c.Assert(ttl, Equals, -1)
+
+	val, ok, err = rs.c.GetDel(k)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+	c.Assert(val, Equals, "test")
+
+	_, ok, err = rs.c.GetDel(k)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	c.Assert(rs.c.Cmd("SADD", k, "test").Err, IsNil)
+
+	_, _, err = rs.c.GetDel(k)
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestGetExOptions(c *C) {
+	at := time.Unix(1700000000, 0)
+
+	c.Assert(GetExOptions{}.args(), IsNil)
+	c.Assert(GetExOptions{Persist: true, TTL: time.Second}.args(), DeepEquals, []any{"PERSIST"})
+	c.Assert(GetExOptions{TTL: time.Minute}.args(), DeepEquals, []any{"EX", int64(60)})
+	c.Assert(GetExOptions{TTL: 1500 * time.Millisecond}.args(), DeepEquals, []any{"PX", int64(1500)})
+	c.Assert(GetExOptions{At: at}.args(), DeepEquals, []any{"EXAT", int64(1700000000)})
+	c.Assert(
+		GetExOptions{At: at.Add(250 * time.Millisecond)}.args(),
+		DeepEquals, []any{"PXAT", int64(1700000000250)},
+	)
+}
+
+func (rs *RedySuite) TestSetOptions(c *C) {
+	c.Assert(SetOptions{}.args(), IsNil)
+	c.Assert(SetOptions{TTL: time.Minute, Mode: "nx"}.args(), DeepEquals, []any{"NX", "EX", int64(60)})
+	c.Assert(SetOptions{TTL: 1500 * time.Millisecond}.args(), DeepEquals, []any{"PX", int64(1500)})
+	c.Assert(SetOptions{TTL: time.Minute, KeepTTL: true, Get: true}.args(), DeepEquals, []any{"GET", "KEEPTTL"})
+}
+
+func (rs *RedySuite) TestWatchKeyspace(c *C) {
+	rc := &Client{Addr: rs.c.Addr, ReadTimeout: time.Second}
+
+	err := rc.Connect()
+	c.Assert(err, IsNil)
+
+	c.Assert(rs.c.Cmd("CONFIG", "SET", "notify-keyspace-events", "").Err, IsNil)
+
+	_, err = rc.WatchKeyspace("CONFIG", "")
+	c.Assert(err, Equals, ErrKeyspaceNotifyDisabled)
+
+	c.Assert(rs.c.Cmd("CONFIG", "SET", "notify-keyspace-events", "K$").Err, IsNil)
+
+	defer rs.c.Cmd("CONFIG", "SET", "notify-keyspace-events", "")
+
+	key := "_watch_" + randString(8)
+	w, err := rc.WatchKeyspace("CONFIG", "_watch_*")
+	c.Assert(err, IsNil)
+
+	c.Assert(rs.c.Cmd("SET", key, "test").Err, IsNil)
+
+	event, err := w.Next()
+	c.Assert(err, IsNil)
+	c.Assert(event, NotNil)
+	c.Assert(event.Key, Equals, key)
+	c.Assert(event.Event, Equals, "set")
+
+	c.Assert(w.Close(), IsNil)
+
+	_, err = w.Next()
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestKeyspaceEvents(c *C) {
+	data := "*3\r\n$10\r\npsubscribe\r\n$15\r\n__keyspace@*__:\r\n:1\r\n" +
+		"*4\r\n$8\r\npmessage\r\n$16\r\n__keyspace@*__:*\r\n$19\r\n__keyspace@0__:test\r\n$3\r\ndel\r\n" +
+		"+OK\r\n"
+
+	w := &KeyspaceWatcher{client: newPipeClient(data)}
+
+	event, err := w.Next()
+	c.Assert(err, IsNil)
+	c.Assert(event, DeepEquals, &KeyEvent{Key: "test", Event: "del"})
+
+	_, err = w.Next()
+	c.Assert(err, Equals, ErrNotArray)
+
+	_, err = w.Next()
+	c.Assert(err, NotNil)
+
+	rc := newPipeClient("*2\r\n$22\r\nnotify-keyspace-events\r\n$0\r\n\r\n")
+
+	_, err = rc.WatchKeyspace("MYCONFIG", "")
+	c.Assert(err, Equals, ErrKeyspaceNotifyDisabled)
+
+	cmd, _ := rc.LastCommand()
+	c.Assert(cmd, Equals, "MYCONFIG")
+
+	rc.Close()
+}
+
+func (rs *RedySuite) TestKeyspaceReconnect(c *C) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+
+	defer ln.Close()
+
+	go func() {
+		buf := make([]byte, 128)
+
+		for _, key := range []string{"test1", "test2"} {
+			conn, _ := ln.Accept()
+			conn.Read(buf)
+			conn.Write([]byte(
+				"*3\r\n$10\r\npsubscribe\r\n$15\r\n__keyspace@*__:\r\n:1\r\n" +
+					"*4\r\n$8\r\npmessage\r\n$16\r\n__keyspace@*__:*\r\n$20\r\n__keyspace@0__:" + key + "\r\n$3\r\nset\r\n",
+			))
+			conn.Close()
+		}
+	}()
+
+	rc := &Client{Addr: ln.Addr().String()}
+	c.Assert(rc.Connect(), IsNil)
+
+	w := &KeyspaceWatcher{AutoReconnect: true, client: rc, pattern: "*"}
+	c.Assert(w.subscribe(), IsNil)
+
+	event, err := w.Next()
+	c.Assert(err, IsNil)
+	c.Assert(event, DeepEquals, &KeyEvent{Key: "test1", Event: "set"})
+
+	event, err = w.Next()
+	c.Assert(err, IsNil)
+	c.Assert(event, DeepEquals, &KeyEvent{Reconnected: true})
+	c.Assert(rc.Reconnects, Equals, int64(1))
+
+	event, err = w.Next()
+	c.Assert(err, IsNil)
+	c.Assert(event, DeepEquals, &KeyEvent{Key: "test2", Event: "set"})
+
+	ln.Close()
+
+	_, err = w.Next()
+	c.Assert(err, NotNil)
+
+	c.Assert(w.Close(), Equals, ErrNotConnected)
+
+	_, err = w.Next()
+	c.Assert(err, Equals, ErrNotConnected)
+}
+
+func (rs *RedySuite) TestMultiKeyCommands(c *C) {
+	k1, k2, k3 := randString(12), randString(12), randString(12)
+
+	err := rs.c.MSet(map[string]any{k1: "test", k2: 10})
+	c.Assert(err, IsNil)
+
+	resps, err := rs.c.MGet(k1, k2, k3)
+	c.Assert(err, IsNil)
+	c.Assert(resps, HasLen, 3)
+	c.Assert(resps[2].HasType(NIL), Equals, true)
+
+	strs, err := rs.c.MGetStrings(k1, k2, k3)
+	c.Assert(err, IsNil)
+	c.Assert(strs, DeepEquals, []string{"test", "10", ""})
+
+	bts, err := rs.c.MGetBytes(k1, k2, k3)
+	c.Assert(err, IsNil)
+	c.Assert(bts, DeepEquals, [][]byte{[]byte("test"), []byte("10"), nil})
+
+	c.Assert(rs.c.MSet(map[string]any{}), NotNil)
+}
+
+func (rs *RedySuite) TestTracking(c *C) {
+	wc := &Client{Addr: rs.c.Addr}
+	c.Assert(wc.Connect(), IsNil)
+
+	id, err := wc.Cmd("CLIENT", "ID").Int64()
+	c.Assert(err, IsNil)
+
+	w, err := wc.Invalidations()
+	c.Assert(err, IsNil)
+
+	k := randString(12)
+	c.Assert(rs.c.EnableTracking(TrackingOptions{Redirect: id}), IsNil)
+	c.Assert(rs.c.Cmd("GET", k).Err, IsNil)
+	c.Assert(rs.c.Cmd("SET", k, "1").Err, IsNil)
+
+	select {
+	case keys := <-w.Keys():
+		c.Assert(keys, DeepEquals, []string{k})
+	case <-time.After(time.Second):
+		c.Fatal("No invalidation message received")
+	}
+
+	c.Assert(rs.c.DisableTracking(), IsNil)
+
+	c.Assert(w.Close(), IsNil)
+	_, ok := <-w.Keys()
+	c.Assert(ok, Equals, false)
+}
+
+func (rs *RedySuite) TestInvalidationWatcher(c *C) {
+	_, err := (&Client{}).Invalidations()
+	c.Assert(err, Equals, ErrNotConnected)
+
+	message := "*3\r\n$7\r\nmessage\r\n$20\r\n__redis__:invalidate\r\n*1\r\n$1\r\na\r\n"
+
+	for _, consume := range []bool{true, false} {
+		conn, srv := net.Pipe()
+
+		go func() {
+			buf := make([]byte, 64)
+			srv.Read(buf)
+			srv.Write([]byte("*3\r\n$9\r\nsubscribe\r\n$20\r\n__redis__:invalidate\r\n:1\r\n"))
+			time.Sleep(50 * time.Millisecond)
+			srv.Write([]byte(message))
+			srv.Write([]byte(message))
+			srv.Close()
+		}()
+
+		rc := &Client{
+			ReadTimeout: 10 * time.Millisecond,
+			conn:        conn,
+			respReader:  NewRespReader(conn),
+			writeBuf:    &bytes.Buffer{},
+		}
+
+		w, err := rc.Invalidations()
+		c.Assert(err, IsNil)
+
+		if !consume {
+			// Nobody reads messages, closing must not block
+			time.Sleep(100 * time.Millisecond)
+			c.Assert(w.Close(), IsNil)
+			c.Assert(rc.LastCritical, IsNil)
+			c.Assert(rc.isHealthy(), Equals, false)
+			continue
+		}
+
+		// Message is delivered after idle period longer than read timeout
+		c.Assert(<-w.Keys(), DeepEquals, []string{"a"})
+		c.Assert(<-w.Keys(), DeepEquals, []string{"a"})
+
+		_, ok := <-w.Keys()
+		c.Assert(ok, Equals, false)
+		c.Assert(rc.LastCritical, NotNil)
+		c.Assert(w.Close(), IsNil)
+	}
+}
+
+func (rs *RedySuite) TestTrackingParsing(c *C) {
+	opts := TrackingOptions{
+		Redirect: 10, Prefixes: []string{"a:", "b:"},
+		BCast: true, OptIn: true, OptOut: true, NoLoop: true,
+	}
+
+	c.Assert(opts.args(), DeepEquals, []string{
+		"REDIRECT", "10", "PREFIX", "a:", "PREFIX", "b:",
+		"BCAST", "OPTIN", "OPTOUT", "NOLOOP",
+	})
+
+	c.Assert(TrackingOptions{}.args(), HasLen, 0)
+
+	rr := NewRespReader(bytes.NewBufferString(
+		"*3\r\n$7\r\nmessage\r\n$20\r\n__redis__:invalidate\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n" +
+			"*3\r\n$7\r\nmessage\r\n$20\r\n__redis__:invalidate\r\n*-1\r\n" +
+			"*3\r\n$9\r\nsubscribe\r\n$20\r\n__redis__:invalidate\r\n:1\r\n" +
+			"*3\r\n$7\r\nmessage\r\n$20\r\n__redis__:invalidate\r\n:1\r\n" +
+			"+OK\r\n",
+	))
+
+	keys, ok := parseInvalidation(rr.Read())
+	c.Assert(ok, Equals, true)
+	c.Assert(keys, DeepEquals, []string{"a", "b"})
+
+	keys, ok = parseInvalidation(rr.Read())
+	c.Assert(ok, Equals, true)
+	c.Assert(keys, IsNil)
+
+	_, ok = parseInvalidation(rr.Read())
+	c.Assert(ok, Equals, false)
+
+	_, ok = parseInvalidation(rr.Read())
+	c.Assert(ok, Equals, false)
+
+	_, ok = parseInvalidation(rr.Read())
+	c.Assert(ok, Equals, false)
+}
+
+func (rs *RedySuite) TestTimeouts(c *C) {
+	timeouts := [][2]time.Duration{
+		{0, 0}, {time.Second, 0}, {0, time.Second}, {time.Second, time.Second},
+	}
+
+	for _, t := range timeouts {
+		conn, srv := net.Pipe()
+
+		go func() {
+			buf := make([]byte, 64)
+			srv.Read(buf)
+			srv.Write([]byte("+PONG\r\n"))
+			srv.Close()
+		}()
+
+		dc := &deadlineConn{Conn: conn}
+		rc := &Client{
+			ReadTimeout:  t[0],
+			WriteTimeout: t[1],
+			conn:         dc,
+			respReader:   NewRespReader(dc),
+			writeBuf:     &bytes.Buffer{},
+		}
+
+		c.Assert(rc.Cmd("PING").Err, IsNil)
+		c.Assert(dc.readDeadline, Equals, t[0] > 0)
+		c.Assert(dc.writeDeadline, Equals, t[1] > 0)
+
+		conn.Close()
+	}
+}
+
+func (rs *RedySuite) TestCmdRaw(c *C) {
+	val, err := rs.c.CmdRaw([]byte("*2\r\n$4\r\nECHO\r\n$4\r\nTEST\r\n")).Str()
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "TEST")
+
+	c.Assert((&Client{}).CmdRaw(nil).Err, Equals, ErrNotConnected)
+
+	conn, srv := net.Pipe()
+	srv.Close()
+
+	dc := &deadlineConn{Conn: conn}
+	rc := &Client{WriteTimeout: time.Second, conn: dc}
+
+	c.Assert(rc.CmdRaw([]byte("PING\r\n")).HasType(ERR_IO), Equals, true)
+	c.Assert(rc.LastCritical, NotNil)
+	c.Assert(dc.writeDeadline, Equals, true)
+}
+
+func (rs *RedySuite) TestZAdd(c *C) {
+	k := randString(12)
+
+	added, err := rs.c.ZAdd(k, map[string]float64{"a": 1, "b": math.Inf(1)}, ZAddOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(added, Equals, int64(2))
+
+	added, err = rs.c.ZAdd(k, map[string]float64{"a": 5, "c": 2}, ZAddOptions{Compare: COND_GT, CH: true})
+	c.Assert(err, IsNil)
+	c.Assert(added, Equals, int64(2))
+
+	added, err = rs.c.ZAdd(k, map[string]float64{"a": 10}, ZAddOptions{Mode: COND_NX})
+	c.Assert(err, IsNil)
+	c.Assert(added, Equals, int64(0))
+
+	score, err := rs.c.ZAddIncr(k, "a", 1.5, ZAddOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(score, Equals, 6.5)
+
+	_, err = rs.c.ZAddIncr(k, "a", 1.5, ZAddOptions{Mode: COND_NX})
+	c.Assert(err, Equals, ErrRespNil)
+
+	_, err = rs.c.ZAdd(k, map[string]float64{"a": 1}, ZAddOptions{Mode: COND_NX, Compare: COND_GT})
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestZAddArgs(c *C) {
+	opts := ZAddOptions{Mode: COND_XX, Compare: COND_LT, CH: true}
+
+	c.Assert(opts.args(), DeepEquals, []string{"XX", "LT", "CH"})
+	c.Assert(ZAddOptions{}.args(), HasLen, 0)
+
+	c.Assert(
+		zaddPairs(map[string]float64{"c": math.Inf(-1), "a": 1.25, "b": math.Inf(1)}),
+		DeepEquals, []string{"1.25", "a", "+inf", "b", "-inf", "c"},
+	)
+
+	c.Assert(string(appendFloat(nil, math.NaN())), Equals, "nan")
+	c.Assert(zaddPairs(nil), HasLen, 0)
+}
+
+func (rs *RedySuite) TestZRangeBy(c *C) {
+	k := randString(12)
+
+	_, err := rs.c.ZAdd(k, map[string]float64{"a": 1, "b": 2, "c": 3, "d": 4}, ZAddOptions{})
+	c.Assert(err, IsNil)
+
+	scores, err := rs.c.ZRangeByScore(k, ScoreBound(1, true), "+inf", ZRangeOptions{Count: 2})
+	c.Assert(err, IsNil)
+	c.Assert(scores, DeepEquals, map[string]float64{"b": 2, "c": 3})
+
+	members, err := rs.c.ZRangeByScoreMembers(k, "-inf", ScoreBound(3, false), ZRangeOptions{Offset: 1})
+	c.Assert(err, IsNil)
+	c.Assert(members, DeepEquals, []string{"b", "c"})
+
+	_, err = rs.c.ZAdd(k, map[string]float64{"a": 0, "b": 0, "c": 0, "d": 0}, ZAddOptions{})
+	c.Assert(err, IsNil)
+
+	members, err = rs.c.ZRangeByLex(k, LexBound("b", false), "+", ZRangeOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(members, DeepEquals, []string{"b", "c", "d"})
+
+	_, err = rs.c.ZRangeByScore(k, "abc", "+inf", ZRangeOptions{})
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestZRangeArgs(c *C) {
+	c.Assert(ZRangeOptions{}.args(), HasLen, 0)
+	c.Assert(ZRangeOptions{Count: 10}.args(), DeepEquals, []any{"LIMIT", 0, 10})
+	c.Assert(ZRangeOptions{Offset: 5}.args(), DeepEquals, []any{"LIMIT", 5, -1})
+
+	c.Assert(ScoreBound(1.5, false), Equals, "1.5")
+	c.Assert(ScoreBound(2, true), Equals, "(2")
+	c.Assert(ScoreBound(math.Inf(-1), false), Equals, "-inf")
+	c.Assert(ScoreBound(math.Inf(1), true), Equals, "(+inf")
+	c.Assert(LexBound("a", false), Equals, "[a")
+	c.Assert(LexBound("a", true), Equals, "(a")
+}
+
+func (rs *RedySuite) TestListCommands(c *C) {
+	k := randString(12)
+
+	size, err := rs.c.RPush(k, "b", []byte("c"), 4)
+	c.Assert(err, IsNil)
+	c.Assert(size, Equals, int64(3))
+
+	size, err = rs.c.LPush(k, "a")
+	c.Assert(err, IsNil)
+	c.Assert(size, Equals, int64(4))
+
+	items, err := rs.c.LRange(k, 0, -1)
+	c.Assert(err, IsNil)
+	c.Assert(items, DeepEquals, []string{"a", "b", "c", "4"})
+
+	items, err = rs.c.LRange(k, 1, 2)
+	c.Assert(err, IsNil)
+	c.Assert(items, DeepEquals, []string{"b", "c"})
+
+	_, err = rs.c.RPush(k, "b", "b")
+	c.Assert(err, IsNil)
+
+	pos, err := rs.c.LPos(k, "b", LPosOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(pos, DeepEquals, []int64{1})
+
+	pos, err = rs.c.LPos(k, "b", LPosOptions{WithCount: true})
+	c.Assert(err, IsNil)
+	c.Assert(pos, DeepEquals, []int64{1, 4, 5})
+
+	pos, err = rs.c.LPos(k, "b", LPosOptions{Rank: -1, Count: 2, WithCount: true, MaxLen: 3})
+	c.Assert(err, IsNil)
+	c.Assert(pos, DeepEquals, []int64{5, 4})
+
+	pos, err = rs.c.LPos(k, "x", LPosOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(pos, HasLen, 0)
+
+	_, err = rs.c.LPush(k)
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestLPosArgs(c *C) {
+	c.Assert(LPosOptions{}.args(), HasLen, 0)
+	c.Assert(
+		LPosOptions{Rank: -2, WithCount: true, MaxLen: 10}.args(), DeepEquals,
+		[]any{"RANK", -2, "COUNT", 0, "MAXLEN", 10},
+	)
+}
+
+func (rs *RedySuite) TestKeyCommands(c *C) {
+	k1, k2, k3 := randString(12), randString(12), randString(12)
+
+	c.Assert(rs.c.Cmd("SET", k1, "1").Err, IsNil)
+
+	ok, err := rs.c.Copy(k1, k2, CopyOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	ok, err = rs.c.Copy(k1, k2, CopyOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	ok, err = rs.c.Copy(k1, k2, CopyOptions{Replace: true})
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	ok, err = rs.c.Copy(k1, k2, CopyOptions{DB: 1, ToDB: true})
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	ok, err = rs.c.RenameNX(k1, k2)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	c.Assert(rs.c.Rename(k1, k3), IsNil)
+	c.Assert(rs.c.Rename(k1, k3), ErrorMatches, "ERR no such key")
+
+	ok, err = rs.c.RenameNX(k3, k1)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	c.Assert(CopyOptions{DB: 3, ToDB: true, Replace: true}.args(), DeepEquals, []any{"DB", 3, "REPLACE"})
+	c.Assert(CopyOptions{DB: 3}.args(), HasLen, 0)
+}
+
+func (rs *RedySuite) TestCmdWaitReady(c *C) {
+	conn, srv := net.Pipe()
+
+	go func() {
+		buf := make([]byte, 64)
+
+		for _, reply := range []string{
+			"-LOADING Redis is loading the dataset in memory\r\n",
+			"-LOADING Redis is loading the dataset in memory\r\n",
+			"+PONG\r\n",
+			"-LOADING Redis is loading the dataset in memory\r\n",
+			"-LOADING Redis is loading the dataset in memory\r\n",
+		} {
+			srv.Read(buf)
+			srv.Write([]byte(reply))
+		}
+
+		srv.Close()
+	}()
+
+	rc := &Client{conn: conn, respReader: NewRespReader(conn), writeBuf: &bytes.Buffer{}}
+
+	val, err := rc.CmdWaitReady(time.Second, "PING").Str()
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "PONG")
+
+	resp := rc.CmdWaitReady(50*time.Millisecond, "PING")
+	c.Assert(resp.ErrPrefix(), Equals, "LOADING")
+
+	c.Assert(rc.CmdWaitReady(time.Second, "PING").HasType(ERR_IO), Equals, true)
+}
+
+func (rs *RedySuite) TestSetRandomCommands(c *C) {
+	k := randString(12)
+
+	items, err := rs.c.SPop(k, 2)
+	c.Assert(err, IsNil)
+	c.Assert(items, HasLen, 0)
+
+	items, err = rs.c.SRandMember(k, 2)
+	c.Assert(err, IsNil)
+	c.Assert(items, HasLen, 0)
+
+	c.Assert(rs.c.Cmd("SADD", k, "a", "b", "c").Err, IsNil)
+
+	items, err = rs.c.SRandMember(k, 2)
+	c.Assert(err, IsNil)
+	c.Assert(items, HasLen, 2)
+
+	items, err = rs.c.SRandMember(k, -5)
+	c.Assert(err, IsNil)
+	c.Assert(items, HasLen, 5)
+
+	items, err = rs.c.SPop(k, 1)
+	c.Assert(err, IsNil)
+	c.Assert(items, HasLen, 1)
+
+	items, err = rs.c.SPop(k, 5)
+	c.Assert(err, IsNil)
+	c.Assert(items, HasLen, 2)
+
+	_, err = rs.c.SPop(k, -1)
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestDrain(c *C) {
+	c.Assert((&Client{}).Drain(), Equals, ErrNotConnected)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+
+	defer ln.Close()
+
+	go func() {
+		conn, _ := ln.Accept()
+		buf := make([]byte, 64)
+		conn.Read(buf)
+		conn.Write([]byte("$5\r\nab\r\n+garbage\r\n"))
+		conn.Read(buf)
+		conn.Write([]byte("+PONG\r\n"))
+		conn.Close()
+
+		conn, _ = ln.Accept()
+		conn.Read(buf)
+		conn.Write([]byte("+PONG\r\n"))
+	}()
+
+	rc := &Client{Addr: ln.Addr().String()}
+	c.Assert(rc.Connect(), IsNil)
+
+	c.Assert(rc.Cmd("GET", "test").HasType(ERR_IO), Equals, false)
+	c.Assert(rc.Drain(), IsNil)
+
+	val, err := rc.Cmd("PING").Str()
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "PONG")
+
+	time.Sleep(10 * time.Millisecond)
+
+	c.Assert(rc.Drain(), IsNil)
+	c.Assert(rc.Reconnects, Equals, int64(1))
+
+	val, err = rc.Cmd("PING").Str()
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "PONG")
+}
+
+func (rs *RedySuite) TestOnConnect(c *C) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+
+	defer ln.Close()
+
+	go func() {
+		buf := make([]byte, 64)
+
+		for _, reply := range []string{"+OK\r\n", "+OK\r\n", "-ERR unknown\r\n"} {
+			conn, _ := ln.Accept()
+			conn.Read(buf)
+			conn.Write([]byte(reply))
+		}
+	}()
+
+	calls := 0
+
+	rc := &Client{
+		Addr: ln.Addr().String(),
+		OnConnect: func(c *Client) error {
+			calls++
+			return c.Cmd("CLIENT", "SETNAME", "test").Err
+		},
+	}
+
+	c.Assert(rc.Connect(), IsNil)
+	c.Assert(rc.Close(), IsNil)
+	c.Assert(rc.Connect(), IsNil)
+	c.Assert(rc.Close(), IsNil)
+	c.Assert(rc.Connect(), ErrorMatches, "ERR unknown")
+	c.Assert(rc.isHealthy(), Equals, false)
+	c.Assert(calls, Equals, 3)
+}
+
+func (rs *RedySuite) TestRecover(c *C) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+
+	defer ln.Close()
+
+	go func() {
+		buf := make([]byte, 64)
+
+		conn, _ := ln.Accept()
+		conn.Read(buf)
+		conn.Close()
+
+		conn, _ = ln.Accept()
+		conn.Read(buf)
+		conn.Write([]byte("+OK\r\n"))
+		conn.Read(buf)
+		conn.Write([]byte("+PONG\r\n"))
+	}()
+
+	calls := 0
+
+	rc := &Client{
+		Addr: ln.Addr().String(),
+		OnConnect: func(c *Client) error {
+			calls++
+
+			if calls == 1 {
+				return nil
+			}
+
+			return c.Cmd("SELECT", 1).Err
+		},
+	}
+
+	c.Assert(rc.Connect(), IsNil)
+	c.Assert(rc.Cmd("PING").HasType(ERR_IO), Equals, true)
+	c.Assert(rc.LastCritical, NotNil)
+
+	rc.PipeAppend("PING")
+
+	c.Assert(rc.Recover(), IsNil)
+	c.Assert(rc.LastCritical, IsNil)
+	c.Assert(rc.pending, HasLen, 0)
+	c.Assert(calls, Equals, 2)
+
+	val, err := rc.Cmd("PING").Str()
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "PONG")
+}
+
+func (rs *RedySuite) TestConfigCommands(c *C) {
+	c.Assert(rs.c.ConfigResetStat("CONFIG"), IsNil)
+
+	info, err := ParseInfo(rs.c.Cmd("INFO", "stats"))
+	c.Assert(err, IsNil)
+	c.Assert(info.Stats().KeyspaceHits, Equals, uint64(0))
+
+	c.Assert(rs.c.ConfigResetStat("UNKNOWN"), NotNil)
+	c.Assert(rs.c.ConfigRewrite("UNKNOWN"), NotNil)
+}
+
+func (rs *RedySuite) TestStrictEncoding(c *C) {
+	type custom struct{ A int }
+
+	c.Assert(checkArgType([]any{"a", 1, 2.5, nil, []byte("b"), []string{"c"}}), IsNil)
+	c.Assert(checkArgType([]any{[]int{1}, map[string]int{"a": 1}, &Resp{val: []byte("a")}}), IsNil)
+	c.Assert(checkArgType(custom{}), ErrorMatches, "Unsupported argument type redy.custom")
+	c.Assert(checkArgType([]any{[]custom{{}}}), NotNil)
+	c.Assert(checkArgType(map[string]custom{"a": {}}), NotNil)
+	c.Assert(checkArgType(map[custom]int{{}: 1}), NotNil)
+	c.Assert(checkArgType(Resp{val: custom{}}), NotNil)
+
+	rc := newPipeClient("")
+	rc.StrictEncoding = true
+	resp := rc.Cmd("SET", "test", custom{})
+
+	c.Assert(errors.Is(resp.Err, ErrBadArgType), Equals, true)
+	c.Assert(rc.LastCritical, IsNil)
+
+	rc.Close()
+}
+
+func (rs *RedySuite) TestExpireCommands(c *C) {
+	k := randString(12)
+
+	ok, err := rs.c.Expire(k, time.Minute, "")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	c.Assert(rs.c.Cmd("SET", k, "1").Err, IsNil)
+
+	ok, err = rs.c.Expire(k, time.Minute, COND_NX)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	ok, err = rs.c.PExpire(k, time.Second, COND_GT)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	ok, err = rs.c.PExpire(k, 1500*time.Millisecond, COND_LT)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	ok, err = rs.c.ExpireAt(k, time.Now().Add(time.Hour).Truncate(time.Second), "")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	ok, err = rs.c.ExpireAt(k, time.Now().Add(time.Hour), COND_XX)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	ok, err = rs.c.Persist(k)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	ok, err = rs.c.Persist(k)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	ok, err = rs.c.Expire(k, 500*time.Millisecond, "")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+	pttl, err := rs.c.Cmd("PTTL", k).Int()
+	c.Assert(err, IsNil)
+	c.Assert(pttl > 0 && pttl <= 500, Equals, true)
+
+	_, err = rs.c.Expire(k, time.Minute, "UNKNOWN")
+	c.Assert(err, NotNil)
+
+	c.Assert(condArgs(""), IsNil)
+	c.Assert(condArgs(COND_GT), DeepEquals, []string{"GT"})
+}
+
+func (rs *RedySuite) TestExpireCommandSelection(c *C) {
+	rc := newPipeClient(":1\r\n:1\r\n:1\r\n")
+
+	_, err := rc.Expire("test", 500*time.Millisecond, "")
+	c.Assert(err, IsNil)
+	cmd, args := rc.LastCommand()
+	c.Assert(cmd, Equals, "PEXPIRE")
+	c.Assert(args[1], Equals, int64(500))
+
+	_, err = rc.Expire("test", 1500*time.Millisecond, COND_NX)
+	c.Assert(err, IsNil)
+	cmd, args = rc.LastCommand()
+	c.Assert(cmd, Equals, "PEXPIRE")
+	c.Assert(args[1], Equals, int64(1500))
+
+	_, err = rc.Expire("test", 2*time.Second, "")
+	c.Assert(err, IsNil)
+	cmd, args = rc.LastCommand()
+	c.Assert(cmd, Equals, "EXPIRE")
+	c.Assert(args[1], Equals, int64(2))
+
+	rc.Close()
+}
+
+func (rs *RedySuite) TestWaitAOF(c *C) {
+	c.Assert(rs.c.Cmd("CONFIG", "SET", "appendonly", "yes").Err, IsNil)
+	c.Assert(rs.c.Cmd("SET", randString(12), "1").Err, IsNil)
+
+	local, replicas, err := rs.c.WaitAOF(1, 0, time.Second)
+	c.Assert(err, IsNil)
+	c.Assert(local, Equals, 1)
+	c.Assert(replicas, Equals, 0)
+
+	c.Assert(rs.c.Cmd("CONFIG", "SET", "appendonly", "no").Err, IsNil)
+}
+
+func (rs *RedySuite) TestBlockingCmd(c *C) {
+	conn, srv := net.Pipe()
+
+	go func() {
+		buf := make([]byte, 64)
+
+		for _, reply := range []string{"*2\r\n:1\r\n:2\r\n", "*1\r\n:1\r\n", "*2\r\n:1\r\n$1\r\nA\r\n", "-ERR\r\n"} {
+			srv.Read(buf)
+			time.Sleep(30 * time.Millisecond)
+			srv.Write([]byte(reply))
+		}
+
+		srv.Close()
+	}()
+
+	dc := &deadlineConn{Conn: conn}
+	rc := &Client{ReadTimeout: 10 * time.Millisecond, conn: dc, respReader: NewRespReader(dc), writeBuf: &bytes.Buffer{}}
+
+	local, replicas, err := rc.WaitAOF(1, 2, 100*time.Millisecond)
+	c.Assert(err, IsNil)
+	c.Assert(local, Equals, 1)
+	c.Assert(replicas, Equals, 2)
+	c.Assert(rc.ReadTimeout, Equals, 10*time.Millisecond)
+
+	_, _, err = rc.WaitAOF(1, 2, 0)
+	c.Assert(err, Equals, ErrUnexpectedResp)
+
+	_, _, err = rc.WaitAOF(1, 2, 0)
+	c.Assert(err, Equals, ErrUnexpectedResp)
+
+	_, _, err = rc.WaitAOF(1, 2, 0)
+	c.Assert(err, ErrorMatches, "ERR")
+}
+
+func (rs *RedySuite) TestCommandGetKeys(c *C) {
+	keys, err := rs.c.CommandGetKeys("MSET", "a", 1, "b", 2)
+	c.Assert(err, IsNil)
+	c.Assert(keys, DeepEquals, []string{"a", "b"})
+
+	keys, err = rs.c.CommandGetKeys("EVAL", "return 1", 2, []string{"k1", "k2"}, "arg")
+	c.Assert(err, IsNil)
+	c.Assert(keys, DeepEquals, []string{"k1", "k2"})
+
+	_, err = rs.c.CommandGetKeys("PING")
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestDebugSleep(c *C) {
+	conn, srv := net.Pipe()
+
+	go func() {
+		buf := make([]byte, 64)
+
+		for _, reply := range []string{"+OK\r\n", "-ERR DEBUG command not allowed\r\n"} {
+			srv.Read(buf)
+			// Reply takes longer than sleep duration and read timeout together
+			time.Sleep(80 * time.Millisecond)
+			srv.Write([]byte(reply))
+		}
+
+		srv.Close()
+	}()
+
+	rc := &Client{ReadTimeout: 10 * time.Millisecond, conn: conn, respReader: NewRespReader(conn), writeBuf: &bytes.Buffer{}}
+
+	c.Assert(rc.DebugSleep(30*time.Millisecond), IsNil)
+	c.Assert(rc.ReadTimeout, Equals, 10*time.Millisecond)
+	c.Assert(rc.DebugSleep(30*time.Millisecond), ErrorMatches, "ERR DEBUG command not allowed")
+}
+
+func (rs *RedySuite) TestRandomFieldCommands(c *C) {
+	hk, zk := randString(12), randString(12)
+
+	c.Assert(rs.c.Cmd("HSET", hk, "a", "1", "b", "2").Err, IsNil)
+	c.Assert(rs.c.Cmd("ZADD", zk, 1.5, "a", 2, "b").Err, IsNil)
+
+	fields, err := rs.c.HRandField(hk, 5, true)
+	c.Assert(err, IsNil)
+	c.Assert(fields, DeepEquals, map[string]string{"a": "1", "b": "2"})
+
+	fields, err = rs.c.HRandField(hk, 5, false)
+	c.Assert(err, IsNil)
+	c.Assert(fields, DeepEquals, map[string]string{"a": "", "b": ""})
+
+	fields, err = rs.c.HRandField(hk, -5, false)
+	c.Assert(err, IsNil)
+	c.Assert(len(fields) > 0, Equals, true)
+
+	members, err := rs.c.ZRandMember(zk, 5, true)
+	c.Assert(err, IsNil)
+	c.Assert(members, DeepEquals, map[string]float64{"a": 1.5, "b": 2})
+
+	members, err = rs.c.ZRandMember(zk, 1, false)
+	c.Assert(err, IsNil)
+	c.Assert(members, HasLen, 1)
+
+	_, err = rs.c.HRandField(zk, 1, false)
+	c.Assert(err, NotNil)
+
+	_, err = rs.c.ZRandMember(hk, 1, true)
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestScoreMapParser(c *C) {
+	r := NewRespReader(bytes.NewBufferString(
+		"*4\r\n$1\r\na\r\n$3\r\n1.5\r\n$1\r\nb\r\n$4\r\n-inf\r\n" +
+			"*1\r\n$1\r\na\r\n" + "*2\r\n:1\r\n$1\r\n1\r\n" + "*2\r\n$1\r\na\r\n$1\r\nA\r\n" + "+OK\r\n",
+	))
+
+	scores, err := parseScoreMap(r.Read())
+	c.Assert(err, IsNil)
+	c.Assert(scores, DeepEquals, map[string]float64{"a": 1.5, "b": math.Inf(-1)})
+
+	_, err = parseScoreMap(r.Read())
+	c.Assert(err, Equals, ErrNotMap)
+
+	for i := 0; i < 3; i++ {
+		_, err = parseScoreMap(r.Read())
+		c.Assert(err, NotNil)
+	}
+}
+
+func (rs *RedySuite) TestSetOperations(c *C) {
+	k1, k2, dst := randString(12), randString(12), randString(12)
+
+	c.Assert(rs.c.Cmd("SADD", k1, "a", "b", "c").Err, IsNil)
+	c.Assert(rs.c.Cmd("SADD", k2, "b", "c", "d").Err, IsNil)
+
+	card, err := rs.c.SInterCard([]string{k1, k2}, 0)
+	c.Assert(err, IsNil)
+	c.Assert(card, Equals, int64(2))
+
+	card, err = rs.c.SInterCard([]string{k1, k2}, 1)
+	c.Assert(err, IsNil)
+	c.Assert(card, Equals, int64(1))
+
+	members, err := rs.c.SInter(k1, k2)
+	c.Assert(err, IsNil)
+	sort.Strings(members)
+	c.Assert(members, DeepEquals, []string{"b", "c"})
+
+	members, err = rs.c.SUnion(k1, k2)
+	c.Assert(err, IsNil)
+	sort.Strings(members)
+	c.Assert(members, DeepEquals, []string{"a", "b", "c", "d"})
+
+	members, err = rs.c.SDiff(k1, k2)
+	c.Assert(err, IsNil)
+	c.Assert(members, DeepEquals, []string{"a"})
+
+	card, err = rs.c.SInterStore(dst, k1, k2)
+	c.Assert(err, IsNil)
+	c.Assert(card, Equals, int64(2))
+
+	card, err = rs.c.SUnionStore(dst, k1, k2)
+	c.Assert(err, IsNil)
+	c.Assert(card, Equals, int64(4))
+
+	card, err = rs.c.SDiffStore(dst, k1, k2)
+	c.Assert(err, IsNil)
+	c.Assert(card, Equals, int64(1))
+
+	_, err = rs.c.SInterCard(nil, 0)
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestConnStats(c *C) {
+	var rc *Client
+
+	c.Assert(rc.Stats(), DeepEquals, ConnStats{})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+
+	defer ln.Close()
+
+	go func() {
+		conn, _ := ln.Accept()
+		buf := make([]byte, 64)
+		conn.Read(buf)
+		conn.Write([]byte("+PONG\r\n"))
+		conn.Read(buf)
+		conn.Write([]byte("$4\r\nTEST\r\n"))
+		conn.Close()
+	}()
+
+	rc = &Client{Addr: ln.Addr().String()}
+	c.Assert(rc.Connect(), IsNil)
+
+	c.Assert(rc.Cmd("PING").Err, IsNil)
+	c.Assert(rc.CmdRaw([]byte("*1\r\n$4\r\nECHO\r\n")).Err, IsNil)
+
+	stats := rc.Stats()
+
+	c.Assert(stats.BytesWritten, Equals, int64(28))
+	c.Assert(stats.BytesRead, Equals, int64(17))
+	c.Assert(stats.Reads, Equals, int64(2))
+}
+
+func (rs *RedySuite) TestReadBufferSize(c *C) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+
+	defer ln.Close()
+
+	reply := "*1024\r\n" + strings.Repeat("$64\r\n"+strings.Repeat("A", 64)+"\r\n", 1024)
+
+	go func() {
+		for i := 0; i < 2; i++ {
+			conn, _ := ln.Accept()
+			buf := make([]byte, 64)
+			conn.Read(buf)
+			conn.Write([]byte(reply))
+			conn.Close()
+		}
+	}()
+
+	for _, size := range []int{0, 256 * 1024} {
+		rc := &Client{Addr: ln.Addr().String(), ReadBufferSize: size}
+		c.Assert(rc.Connect(), IsNil)
+
+		if size == 0 {
+			c.Assert(rc.respReader.r.Size(), Equals, 4096)
+		} else {
+			c.Assert(rc.respReader.r.Size(), Equals, size)
+		}
+
+		items, err := rc.Cmd("LRANGE", "test", 0, -1).List()
+		c.Assert(err, IsNil)
+		c.Assert(items, HasLen, 1024)
+		c.Assert(rc.Stats().BytesRead, Equals, int64(len(reply)))
+
+		rc.Close()
+	}
+}
+
+func (rs *RedySuite) TestSetMembership(c *C) {
+	k := randString(12)
+
+	c.Assert(rs.c.Cmd("SADD", k, "a", "b").Err, IsNil)
+
+	ok, err := rs.c.SIsMember(k, "a")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+
+	ok, err = rs.c.SIsMember(k, "c")
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	flags, err := rs.c.SMIsMember(k, "a", "c", "b")
+	c.Assert(err, IsNil)
+	c.Assert(flags, DeepEquals, []bool{true, false, true})
+
+	_, err = rs.c.SMIsMember(k)
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestScan(c *C) {
+	prefix := randString(8) + ":"
+
+	c.Assert(rs.c.Cmd("SET", prefix+"a", "1").Err, IsNil)
+	c.Assert(rs.c.Cmd("HSET", prefix+"b", "a", "1").Err, IsNil)
+
+	var keys []string
+
+	cursor := "0"
+
+	for {
+		next, items, err := rs.c.Scan(cursor, ScanOptions{Match: prefix + "*", Count: 100, Type: "hash"})
+		c.Assert(err, IsNil)
+
+		keys = append(keys, items...)
+
+		if next == "0" {
+			break
+		}
+
+		cursor = next
+	}
+
+	c.Assert(keys, DeepEquals, []string{prefix + "b"})
+
+	c.Assert(ScanOptions{}.args(), HasLen, 0)
+	c.Assert(
+		ScanOptions{Match: "a*", Count: 10, Type: "set"}.args(),
+		DeepEquals, []any{"MATCH", "a*", "COUNT", 10, "TYPE", "set"},
+	)
+}
+
+func (rs *RedySuite) TestSort(c *C) {
+	k, prefix := randString(12), randString(8)
+
+	c.Assert(rs.c.Cmd("RPUSH", k, 3, 1, 2).Err, IsNil)
+	c.Assert(rs.c.Cmd("MSET", prefix+"_w_1", 30, prefix+"_w_2", 10, prefix+"_w_3", 20).Err, IsNil)
+	c.Assert(rs.c.Cmd("MSET", prefix+"_n_1", "a", prefix+"_n_2", "b", prefix+"_n_3", "c").Err, IsNil)
+
+	items, err := rs.c.Sort(k, SortOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(items, DeepEquals, []string{"1", "2", "3"})
+
+	items, err = rs.c.Sort(k, SortOptions{Order: ORDER_DESC, Count: 2})
+	c.Assert(err, IsNil)
+	c.Assert(items, DeepEquals, []string{"3", "2"})
+
+	items, err = rs.c.Sort(k, SortOptions{By: prefix + "_w_*", Get: []string{"#", prefix + "_n_*"}})
+	c.Assert(err, IsNil)
+	c.Assert(items, DeepEquals, []string{"2", "b", "3", "c", "1", "a"})
+
+	items, err = rs.c.SortRO(k, SortOptions{Alpha: true, Offset: 1})
+	c.Assert(err, IsNil)
+	c.Assert(items, DeepEquals, []string{"2", "3"})
+
+	n, err := rs.c.SortStore(k, k+"_sorted", SortOptions{Order: ORDER_DESC})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(3))
+
+	_, err = rs.c.Sort(k, SortOptions{Order: "UNKNOWN"})
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestSortArgs(c *C) {
+	c.Assert(SortOptions{}.args(), HasLen, 0)
+	c.Assert(
+		SortOptions{By: "w_*", Offset: 5, Get: []string{"#", "n_*"}, Order: ORDER_DESC, Alpha: true}.args(),
+		DeepEquals, []any{"BY", "w_*", "LIMIT", 5, -1, "GET", "#", "GET", "n_*", "DESC", "ALPHA"},
+	)
+	c.Assert(SortOptions{Count: 10}.args(), DeepEquals, []any{"LIMIT", 0, 10})
+}
+
+func (rs *RedySuite) TestEncodingHistogram(c *C) {
+	prefix := randString(8) + ":"
+
+	c.Assert(rs.c.Cmd("SET", prefix+"a", "1").Err, IsNil)
+	c.Assert(rs.c.Cmd("SET", prefix+"b", "test").Err, IsNil)
+	c.Assert(rs.c.Cmd("HSET", prefix+"c", "a", "1").Err, IsNil)
+
+	encoding, err := rs.c.ObjectEncoding(prefix + "a")
+	c.Assert(err, IsNil)
+	c.Assert(encoding, Equals, "int")
+
+	_, err = rs.c.ObjectEncoding(prefix + "unknown")
+	c.Assert(err, Equals, ErrRespNil)
+
+	hist, err := rs.c.EncodingHistogram(prefix+"*", 0)
+	c.Assert(err, IsNil)
+	c.Assert(hist["int"], Equals, 1)
+	c.Assert(hist["embstr"], Equals, 1)
+	c.Assert(hist["listpack"], Equals, 1)
+
+	hist, err = rs.c.EncodingHistogram(prefix+"*", 2)
+	c.Assert(err, IsNil)
+	c.Assert(hist, HasLen, 2)
+}
+
+func (rs *RedySuite) TestReplyMode(c *C) {
+	c.Assert((&Client{}).SetReplyMode(REPLY_OFF), Equals, ErrNotConnected)
+
+	rc := newPipeClient("+OK\r\n+PONG\r\n+PONG\r\n")
+
+	c.Assert(rc.SetReplyMode("unknown"), Equals, ErrUnknownReplyMode)
+	c.Assert(rc.SetReplyMode(REPLY_OFF), IsNil)
+	c.Assert(rc.Cmd("SET", "a", "1").HasType(NIL), Equals, true)
+	c.Assert(rc.SetReplyMode(REPLY_SKIP), IsNil)
+	c.Assert(rc.Cmd("SET", "a", "1").HasType(NIL), Equals, true)
+
+	rc.PipeAppend("SET", "a", "1")
+	rc.PipeAppend("SET", "a", "2")
+	c.Assert(rc.PipeResp().HasType(NIL), Equals, true)
+	c.Assert(rc.PipeResp().HasType(NIL), Equals, true)
+
+	c.Assert(rc.SetReplyMode("on"), IsNil)
+	c.Assert(rc.Cmd("PING").String(), Equals, `Resp(Str "PONG")`)
+	c.Assert(rc.SetReplyMode(REPLY_SKIP), IsNil)
+	c.Assert(rc.Cmd("SET", "a", "1").HasType(NIL), Equals, true)
+	c.Assert(rc.Cmd("PING").String(), Equals, `Resp(Str "PONG")`)
+
+	rc.Close()
+}
+
+func (rs *RedySuite) TestTime(c *C) {
+	t, err := rs.c.Time()
+
+	c.Assert(err, IsNil)
+	c.Assert(time.Since(t) < time.Minute, Equals, true)
+}
+
+func (rs *RedySuite) TestPipeTimeout(c *C) {
+	// Server sends replies with delay, so they can't be scripted
+	newDelayedClient := func(pipeTimeout time.Duration) *Client {
+		conn, srv := net.Pipe()
+
+		go io.Copy(io.Discard, srv)
+
+		go func() {
+			for i := 0; i < 3; i++ {
+				time.Sleep(40 * time.Millisecond)
+				srv.Write([]byte("+PONG\r\n"))
+			}
+
+			srv.Close()
+		}()
+
+		return &Client{
+			ReadTimeout: 100 * time.Millisecond,
+			PipeTimeout: pipeTimeout,
+			conn:        conn,
+			respReader:  NewRespReader(conn),
+			writeBuf:    &bytes.Buffer{},
+		}
+	}
+
+	for _, pipeTimeout := range []time.Duration{0, 60 * time.Millisecond} {
+		rc := newDelayedClient(pipeTimeout)
+
+		rc.PipeAppend("PING")
+		rc.PipeAppend("PING")
+		rc.PipeAppend("PING")
+
+		c.Assert(rc.PipeResp().Err, IsNil)
+
+		if pipeTimeout == 0 {
+			c.Assert(rc.PipeResp().Err, IsNil)
+			c.Assert(rc.PipeResp().Err, IsNil)
+		} else {
+			c.Assert(rc.PipeResp().HasType(ERR_IO), Equals, true)
+			c.Assert(rc.batchDeadline.IsZero(), Equals, true)
+		}
+
+		rc.Close()
+	}
+
+	for _, pipeTimeout := range []time.Duration{0, 60 * time.Millisecond} {
+		rc := newDelayedClient(pipeTimeout)
+		p := rc.Pipeline()
+
+		p.Append("PING")
+		p.Append("PING")
+		p.Append("PING")
+
+		resps, err := p.Exec()
+
+		if pipeTimeout == 0 {
+			c.Assert(err, IsNil)
+			c.Assert(resps, HasLen, 3)
+		} else {
+			c.Assert(err, NotNil)
+			c.Assert(resps, HasLen, 2)
+			c.Assert(resps[1].HasType(ERR_IO), Equals, true)
+			c.Assert(rc.batchDeadline.IsZero(), Equals, true)
+		}
+
+		rc.Close()
+	}
+}
+
+func (rs *RedySuite) TestPipeRespContext(c *C) {
+	conn, srv := net.Pipe()
+
+	go func() {
+		buf := make([]byte, 64)
+		srv.Read(buf)
+		srv.Read(buf)
+		srv.Write([]byte("+PONG\r\n+PONG\r\n"))
+		srv.Read(buf)
+	}()
+
+	rc := &Client{conn: conn, respReader: NewRespReader(conn), writeBuf: &bytes.Buffer{}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	rc.PipeAppend("PING")
+	rc.PipeAppend("PING")
+
+	c.Assert(rc.PipeRespContext(ctx).Err, IsNil)
+	c.Assert(rc.PipeRespContext(ctx).Err, IsNil)
+	c.Assert(rc.PipeRespContext(ctx).Err, Equals, ErrEmptyPipeline)
+
+	rc.PipeAppend("PING")
+
+	resp := rc.PipeRespContext(ctx)
+
+	c.Assert(resp.HasType(ERR_IO), Equals, true)
+	c.Assert(resp.Err, Equals, context.DeadlineExceeded)
+	c.Assert(rc.LastCritical, Equals, context.DeadlineExceeded)
+	c.Assert(rc.isHealthy(), Equals, false)
+
+	rc = newPipeClient("")
+	rc.PipeAppend("PING")
+
+	c.Assert(rc.PipeRespContext(ctx).Err, Equals, context.DeadlineExceeded)
+	c.Assert(rc.pending, HasLen, 0)
+	c.Assert(rc.isHealthy(), Equals, true)
+
+	rc.Close()
+
+	// Context is done right after reply is read
+	conn, srv = net.Pipe()
+
+	go func() {
+		buf := make([]byte, 64)
+		srv.Read(buf)
+		srv.Write([]byte("+PONG\r\n"))
+		srv.Read(buf)
+	}()
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cc := &cancelConn{Conn: conn, cancel: cancel}
+	rc = &Client{conn: cc, respReader: NewRespReader(cc), writeBuf: &bytes.Buffer{}}
+
+	rc.PipeAppend("PING")
+
+	c.Assert(rc.PipeRespContext(ctx).Err, IsNil)
+	c.Assert(rc.isHealthy(), Equals, true)
+	c.Assert(rc.LastCritical, IsNil)
+
+	rc.Close()
+}
+
+func (rs *RedySuite) TestMaxPipelineDepth(c *C) {
+	rc := newPipeClient(":1\r\n:2\r\n:3\r\n:4\r\n:5\r\n")
+	rc.MaxPipelineDepth = 2
+
+	for i := 0; i < 3; i++ {
+		rc.PipeAppend("INCR", "test")
+	}
+
+	c.Assert(rc.pending, HasLen, 1)
+	c.Assert(rc.completed, HasLen, 2)
+	c.Assert(rc.PipeResp().String(), Equals, "Resp(Int 1)")
+
+	rc.PipeAppend("INCR", "test")
+
+	c.Assert(rc.pending, HasLen, 0)
+	c.Assert(rc.completed, HasLen, 3)
+
+	rc.PipeAppend("INCR", "test")
+
+	for i := 2; i <= 5; i++ {
+		val, err := rc.PipeResp().Int()
+		c.Assert(err, IsNil)
+		c.Assert(val, Equals, i)
+	}
+
+	c.Assert(rc.PipeResp().Err, Equals, ErrEmptyPipeline)
+
+	rc.conn.Close()
+
+	rc.PipeAppend("INCR", "test")
+	rc.PipeAppend("INCR", "test")
+
+	c.Assert(rc.pending, HasLen, 0)
+	c.Assert(rc.PipeResp().HasType(ERR_IO), Equals, true)
+	c.Assert(rc.PipeResp().HasType(ERR_IO), Equals, true)
+}
+
+func (rs *RedySuite) TestLastCommand(c *C) {
+	var rc *Client
+
+	cmd, args := rc.LastCommand()
+	c.Assert(cmd, Equals, "")
+	c.Assert(args, IsNil)
+
+	rc = newPipeClient("+OK\r\n:1\r\n:2\r\n")
+
+	rc.Cmd("SET", "test", 1)
+
+	cmd, args = rc.LastCommand()
+	c.Assert(cmd, Equals, "SET")
+	c.Assert(args, DeepEquals, []any{"test", 1})
+
+	rc.PipeAppend("INCR", "a")
+	rc.PipeAppend("INCR", "b")
+	rc.PipeResp()
+
+	cmd, args = rc.LastCommand()
+	c.Assert(cmd, Equals, "INCR")
+	c.Assert(args, DeepEquals, []any{"b"})
+
+	rc.Close()
+}
+
+func (rs *RedySuite) TestRoutingClient(c *C) {
+	primary := newPipeClient("+OK\r\n$7\r\nprimary\r\n$7\r\nprimary\r\n")
+	replica1 := newPipeClient("$8\r\nreplica1\r\n")
+	replica2 := newPipeClient("$8\r\nreplica2\r\n")
+
+	rc := NewRoutingClient(primary, replica1, replica2)
+
+	c.Assert(rc.Cmd("SET", "a", "1").String(), Equals, `Resp(Str "OK")`)
+	c.Assert(rc.Cmd("get", "a").String(), Equals, `Resp(BulkStr "replica1")`)
+	c.Assert(rc.Cmd("GET", "a").String(), Equals, `Resp(BulkStr "replica2")`)
+
+	// Both replicas have no more replies, so read fails with I/O error
+	c.Assert(rc.Cmd("GET", "a").String(), Equals, `Resp(BulkStr "primary")`)
+	c.Assert(replica1.isHealthy(), Equals, false)
+	c.Assert(rc.Cmd("GET", "a").String(), Equals, `Resp(BulkStr "primary")`)
+	c.Assert(replica2.isHealthy(), Equals, false)
+
+	c.Assert(rc.Close(), IsNil)
+	c.Assert(primary.isHealthy(), Equals, false)
+}
+
+func (rs *RedySuite) TestReconnect(c *C) {
+	rs.c.Close()
+	err := rs.c.Connect()
+
+	c.Assert(err, IsNil)
+}
+
+func (rs *RedySuite) TestLastCritical(c *C) {
+	rc := &Client{
+		Addr: rs.c.Addr,
+	}
+
+	err := rc.Connect()
+	c.Assert(err, IsNil)
+
+	err = rc.Cmd("UNKNOWN_COMMAND").Err
+	c.Assert(err, NotNil)
+	c.Assert(rc.LastCritical, IsNil)
+
+	rc.Close()
+
+	err = rc.Cmd("UNKNOWN_COMMAND").Err
+	c.Assert(err, NotNil)
+	c.Assert(rc.LastCritical, NotNil)
+}
+
+func (rs *RedySuite) TestRespRead(c *C) {
+	var r *Resp
+	var err error
+
+	r = pretendRead("")
+	c.Assert(r.Err, NotNil)
+
+	// Simple string
+	r = pretendRead("+TEST1234\r\n")
+	c.Assert(r.HasType(STR_SIMPLE), Equals, true)
+	c.Assert(r.val, DeepEquals, []byte("TEST1234"))
+	s, err := r.Str()
+	c.Assert(err, IsNil)
+	c.Assert(s, Equals, "TEST1234")
+	c.Assert(r.String(), Equals, "Resp(Str \"TEST1234\")")
+
+	// Empty simple string
+	r = pretendRead("+\r\n")
+	c.Assert(r.HasType(STR_SIMPLE), Equals, true)
+	c.Assert(r.val, DeepEquals, []byte(""))
+	s, err = r.Str()
+	c.Assert(err, IsNil)
+	c.Assert(s, Equals, "")
+	c.Assert(r.String(), Equals, "Resp(Str \"\")")
+
+	// Error
+	r = pretendRead("-TEST1234\r\n")
+	c.Assert(r.HasType(ERR_REDIS), Equals, true)
+	c.Assert(r.val, DeepEquals, errors.New("TEST1234"))
+	c.Assert(r.Err.Error(), Equals, "TEST1234")
+	c.Assert(r.String(), Equals, "Resp(RedisErr \"TEST1234\")")
+
+	// Empty error
+	r = pretendRead("-\r\n")
+	c.Assert(r.HasType(ERR_REDIS), Equals, true)
+	c.Assert(r.val, DeepEquals, errors.New(""))
+	c.Assert(r.Err.Error(), Equals, "")
+	c.Assert(r.String(), Equals, "Resp(RedisErr \"\")")
+
+	// Int
+	r = pretendRead(":1024\r\n")
+	c.Assert(r.HasType(INT), Equals, true)
+	c.Assert(r.val, Equals, int64(1024))
+	i, err := r.Int()
+	c.Assert(err, IsNil)
+	c.Assert(i, Equals, 1024)
+	f64, err := r.Float64()
+	c.Assert(err, IsNil)
+	c.Assert(f64, Equals, 1024.0)
+	c.Assert(r.String(), Equals, "Resp(Int 1024)")
+
+	// Check int max
+	maxInt = 10
+	i, err = r.Int()
+	c.Assert(err, IsNil)
+	c.Assert(i, Equals, 10)
+	maxInt = int(^uint(0) >> 1)
+
+	// Int (from string)
+	r = pretendRead("+50\r\n")
+	c.Assert(r.HasType(STR_SIMPLE), Equals, true)
+	c.Assert(r.val, DeepEquals, []byte("50"))
+	i, err = r.Int()
+	c.Assert(err, IsNil)
+	c.Assert(i, Equals, 50)
+	i64, err := r.Int64()
+	c.Assert(err, IsNil)
+	c.Assert(i64, Equals, int64(50))
+	f, err := r.Float64()
+	c.Assert(err, IsNil)
+	c.Assert(f, Equals, 50.0)
+	c.Assert(r.String(), Equals, "Resp(Str \"50\")")
+
+	// Int (from string, can't parse)
+	r = pretendRead("+TEST1234\r\n")
+	c.Assert(r.HasType(STR_SIMPLE), Equals, true)
+	c.Assert(r.val, DeepEquals, []byte("TEST1234"))
+	_, err = r.Int()
+	c.Assert(err, NotNil)
+	c.Assert(r.String(), Equals, "Resp(Str \"TEST1234\")")
+
+	// Bulk string
+	r = pretendRead("$8\r\nTEST1234\r\n")
+	c.Assert(r.HasType(STR_BULK), Equals, true)
+	c.Assert(r.val, DeepEquals, []byte("TEST1234"))
+	s, err = r.Str()
+	c.Assert(err, IsNil)
+	c.Assert(s, Equals, "TEST1234")
+	c.Assert(r.String(), Equals, "Resp(BulkStr \"TEST1234\")")
+
+	// Empty bulk string
+	r = pretendRead("$0\r\n\r\n")
+	c.Assert(r.HasType(STR_BULK), Equals, true)
+	c.Assert(r.val, DeepEquals, []byte(""))
+	s, err = r.Str()
+	c.Assert(err, IsNil)
+	c.Assert(s, Equals, "")
+	c.Assert(r.String(), Equals, "Resp(BulkStr \"\")")
+
+	// Nil bulk string
+	r = pretendRead("$-1\r\n")
+	c.Assert(r.HasType(NIL), Equals, true)
+	c.Assert(r.String(), Equals, "Resp(Nil)")
+
+	// Array
+	r = pretendRead("*2\r\n+TEST\r\n+1234\r\n")
+	c.Assert(r.HasType(ARRAY), Equals, true)
+	c.Assert(len(r.val.([]Resp)), Equals, 2)
+	c.Assert(r.val.([]Resp)[0].HasType(STR_SIMPLE), Equals, true)
+	c.Assert(r.val.([]Resp)[0].val, DeepEquals, []byte("TEST"))
+	c.Assert(r.val.([]Resp)[1].HasType(STR_SIMPLE), Equals, true)
+	c.Assert(r.val.([]Resp)[1].val, DeepEquals, []byte("1234"))
+
+	l, err := r.List()
+	c.Assert(err, IsNil)
+	c.Assert(l, DeepEquals, []string{"TEST", "1234"})
+
+	lb, err := r.ListBytes()
+	c.Assert(err, IsNil)
+	c.Assert(lb, DeepEquals, [][]byte{[]byte("TEST"), []byte("1234")})
+
+	m, err := r.Map()
+	c.Assert(err, IsNil)
+	c.Assert(m, DeepEquals, map[string]string{"TEST": "1234"})
+	c.Assert(r.String(), Equals, "Resp(0:Resp(Str \"TEST\") 1:Resp(Str \"1234\"))")
+
+	// Array with nil
+	r = pretendRead("*3\r\n$0\r\n\r\n$-1\r\n$3\r\nabc\r\n")
+
+	l, err = r.Strings()
+	c.Assert(err, IsNil)
+	c.Assert(l, DeepEquals, []string{"", "", "abc"})
+
+	ln, err := r.ListWithNils()
+	c.Assert(err, IsNil)
+	c.Assert(ln, HasLen, 3)
+	c.Assert(*ln[0], Equals, "")
+	c.Assert(ln[1], IsNil)
+	c.Assert(*ln[2], Equals, "abc")
+
+	_, err = pretendRead("+OK\r\n").ListWithNils()
+	c.Assert(err, Equals, ErrNotArray)
+	_, err = pretendRead("-ERR\r\n").ListWithNils()
+	c.Assert(err, NotNil)
+	_, err = pretendRead("*1\r\n:1\r\n").ListWithNils()
+	c.Assert(err, NotNil)
 
 	// Empty Array
 	r = pretendRead("*0\r\n")
@@ -330,38 +2484,530 @@ func (rs *RedySuite) TestRespRead(c *C) {
 	c.Assert(r.HasType(NIL), Equals, true)
 	c.Assert(r.String(), Equals, "Resp(Nil)")
 
-	// Embedded Array
-	r = pretendRead("*3\r\n+TEST\r\n+1234\r\n*2\r\n+STUB\r\n+5678\r\n")
-	c.Assert(r.String(), Equals, "Resp(0:Resp(Str \"TEST\") 1:Resp(Str \"1234\") 2:Resp(0:Resp(Str \"STUB\") 1:Resp(Str \"5678\")))")
-	c.Assert(r.HasType(ARRAY), Equals, true)
-	c.Assert(len(r.val.([]Resp)), Equals, 3)
-	c.Assert(r.val.([]Resp)[0].HasType(STR_SIMPLE), Equals, true)
-	c.Assert(r.val.([]Resp)[0].val, DeepEquals, []byte("TEST"))
-	c.Assert(r.val.([]Resp)[1].HasType(STR_SIMPLE), Equals, true)
-	c.Assert(r.val.([]Resp)[1].val, DeepEquals, []byte("1234"))
-	r = &r.val.([]Resp)[2]
-	c.Assert(r.HasType(ARRAY), Equals, true)
-	c.Assert(len(r.val.([]Resp)), Equals, 2)
-	c.Assert(r.val.([]Resp)[0].HasType(STR_SIMPLE), Equals, true)
-	c.Assert(r.val.([]Resp)[0].val, DeepEquals, []byte("STUB"))
-	c.Assert(r.val.([]Resp)[1].HasType(STR_SIMPLE), Equals, true)
-	c.Assert(r.val.([]Resp)[1].val, DeepEquals, []byte("5678"))
+	// Embedded Array
+	r = pretendRead("*3\r\n+TEST\r\n+1234\r\n*2\r\n+STUB\r\n+5678\r\n")
+	c.Assert(r.String(), Equals, "Resp(0:Resp(Str \"TEST\") 1:Resp(Str \"1234\") 2:Resp(0:Resp(Str \"STUB\") 1:Resp(Str \"5678\")))")
+	c.Assert(r.HasType(ARRAY), Equals, true)
+	c.Assert(len(r.val.([]Resp)), Equals, 3)
+	c.Assert(r.val.([]Resp)[0].HasType(STR_SIMPLE), Equals, true)
+	c.Assert(r.val.([]Resp)[0].val, DeepEquals, []byte("TEST"))
+	c.Assert(r.val.([]Resp)[1].HasType(STR_SIMPLE), Equals, true)
+	c.Assert(r.val.([]Resp)[1].val, DeepEquals, []byte("1234"))
+	r = &r.val.([]Resp)[2]
+	c.Assert(r.HasType(ARRAY), Equals, true)
+	c.Assert(len(r.val.([]Resp)), Equals, 2)
+	c.Assert(r.val.([]Resp)[0].HasType(STR_SIMPLE), Equals, true)
+	c.Assert(r.val.([]Resp)[0].val, DeepEquals, []byte("STUB"))
+	c.Assert(r.val.([]Resp)[1].HasType(STR_SIMPLE), Equals, true)
+	c.Assert(r.val.([]Resp)[1].val, DeepEquals, []byte("5678"))
+
+	// Test that two bulks in a row read correctly
+	r = pretendRead("*2\r\n$4\r\nTEST\r\n$4\r\n1234\r\n")
+	c.Assert(r.String(), Equals, "Resp(0:Resp(BulkStr \"TEST\") 1:Resp(BulkStr \"1234\"))")
+	c.Assert(r.HasType(ARRAY), Equals, true)
+	c.Assert(len(r.val.([]Resp)), Equals, 2)
+	c.Assert(r.val.([]Resp)[0].HasType(STR_BULK), Equals, true)
+	c.Assert(r.val.([]Resp)[0].val, DeepEquals, []byte("TEST"))
+	c.Assert(r.val.([]Resp)[1].HasType(STR_BULK), Equals, true)
+	c.Assert(r.val.([]Resp)[1].val, DeepEquals, []byte("1234"))
+
+	r = &Resp{}
+	c.Assert(r.String(), Equals, "Resp(Unknown)")
+
+	r = &Resp{typ: ERR_IO, Err: errors.New("IOERR")}
+	c.Assert(r.String(), Equals, "Resp(ErrIO \"IOERR\")")
+}
+
+func (rs *RedySuite) TestRespRaw(c *C) {
+	data := "*3\r\n$4\r\nTEST\r\n:0012\r\n*-1\r\n"
+	rr := NewRespReader(bytes.NewBufferString(data + "+OK\r\n"))
+	rr.KeepRaw = true
+
+	r := rr.Read()
+	c.Assert(r.Err, IsNil)
+	c.Assert(string(r.Raw()), Equals, data)
+
+	r = rr.Read()
+	c.Assert(r.Err, IsNil)
+	c.Assert(string(r.Raw()), Equals, "+OK\r\n")
+
+	r = rr.Read()
+	c.Assert(r.Err, NotNil)
+	c.Assert(r.Raw(), IsNil)
+
+	r = pretendRead("+OK\r\n")
+	c.Assert(r.Err, IsNil)
+	c.Assert(r.Raw(), IsNil)
+
+	r = nil
+	c.Assert(r.Raw(), IsNil)
+
+	for _, keepRaw := range []bool{true, false} {
+		rc := &Client{
+			KeepRaw: keepRaw,
+			DialFunc: func(network, addr string) (net.Conn, error) {
+				conn, srv := net.Pipe()
+
+				go func() {
+					buf := make([]byte, 64)
+					srv.Read(buf)
+					srv.Write([]byte("$4\r\nTEST\r\n"))
+					io.Copy(io.Discard, srv)
+				}()
+
+				return conn, nil
+			},
+		}
+
+		c.Assert(rc.Connect(), IsNil)
+
+		r = rc.Cmd("ECHO", "TEST")
+		c.Assert(r.Err, IsNil)
+
+		if keepRaw {
+			c.Assert(string(r.Raw()), Equals, "$4\r\nTEST\r\n")
+		} else {
+			c.Assert(r.Raw(), IsNil)
+		}
+
+		rc.Close()
+	}
+}
+
+func (rs *RedySuite) TestRespIsOK(c *C) {
+	c.Assert(pretendRead("+OK\r\n").IsOK(), Equals, true)
+	c.Assert(pretendRead("+QUEUED\r\n").IsOK(), Equals, false)
+	c.Assert(pretendRead("$2\r\nOK\r\n").IsOK(), Equals, false)
+	c.Assert(pretendRead("-OK\r\n").IsOK(), Equals, false)
+	c.Assert(pretendRead("$-1\r\n").IsOK(), Equals, false)
+	c.Assert(pretendRead(":1\r\n").IsOK(), Equals, false)
+	c.Assert(pretendRead("").IsOK(), Equals, false)
+
+	var r *Resp
+	c.Assert(r.IsOK(), Equals, false)
+}
+
+func (rs *RedySuite) TestRespForEach(c *C) {
+	r := pretendRead("*3\r\n:1\r\n:2\r\n:3\r\n")
+
+	var sum int64
+
+	err := r.ForEach(func(i int, ir *Resp) error {
+		v, err := ir.Int64()
+		sum += v
+		return err
+	})
+
+	c.Assert(err, IsNil)
+	c.Assert(sum, Equals, int64(6))
+
+	var count int
+
+	err = r.ForEach(func(i int, ir *Resp) error {
+		count++
+
+		if i == 1 {
+			return errors.New("STOP")
+		}
+
+		return nil
+	})
+
+	c.Assert(err, ErrorMatches, "STOP")
+	c.Assert(count, Equals, 2)
+
+	noop := func(i int, ir *Resp) error { return nil }
+
+	c.Assert(pretendRead("+OK\r\n").ForEach(noop), Equals, ErrNotArray)
+	c.Assert(pretendRead("").ForEach(noop), NotNil)
+}
+
+func (rs *RedySuite) TestScratchBuffer(c *C) {
+	rc := &Client{Addr: rs.c.Addr}
+
+	err := rc.Connect()
+	c.Assert(err, IsNil)
+	c.Assert(rc.writeScratch, NotNil)
+	c.Assert(cap(rc.writeScratch) > 0, Equals, true)
+
+	val, err := rc.Cmd("ECHO", 1234).Str()
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "1234")
+
+	val, err = rc.Cmd("ECHO", 12.5).Str()
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "12.5")
+
+	rc.Close()
+}
+
+func (rs *RedySuite) TestRespAsError(c *C) {
+	r := NewRespReader(bytes.NewBufferString("+OK\r\n$1\r\nA\r\n:1\r\n*0\r\n$-1\r\n-ERR error\r\n"))
+
+	for i := 0; i < 4; i++ {
+		c.Assert(r.Read().AsError(), IsNil)
+	}
+
+	c.Assert(r.Read().AsError(), Equals, ErrRespNil)
+	c.Assert(r.Read().AsError(), ErrorMatches, "ERR error")
+	c.Assert(r.Read().AsError(), NotNil)
+
+	var resp *Resp
+
+	c.Assert(resp.AsError(), Equals, ErrRespNil)
+	c.Assert((&Resp{}).AsError(), Equals, ErrBadType)
+}
+
+func (rs *RedySuite) TestRespWriteReply(c *C) {
+	data := "+OK\r\n" + "$3\r\nabc\r\n" + ":-12\r\n" + "$-1\r\n" + "-ERR error\r\n" +
+		"*3\r\n:1\r\n*1\r\n+A\r\n$0\r\n\r\n" +
+		"|2\r\n$1\r\na\r\n:1\r\n$1\r\nb\r\n+B\r\n:5\r\n"
+
+	r := NewRespReader(bytes.NewBufferString(data))
+	buf := &bytes.Buffer{}
+
+	for i := 0; i < 7; i++ {
+		resp := r.Read()
+		c.Assert(resp.HasType(ERR_IO), Equals, false)
+
+		_, err := resp.WriteReply(buf)
+		c.Assert(err, IsNil)
+	}
+
+	c.Assert(buf.String(), Equals, data)
+
+	var resp *Resp
+
+	n, err := resp.WriteReply(buf)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, 5)
+
+	ioErr := errToResp(ERR_IO, ErrParse)
+	_, err = ioErr.WriteReply(buf)
+	c.Assert(err, Equals, ErrBadType)
+
+	_, err = pretendRead(":1\r\n").WriteReply(&errWriter{})
+	c.Assert(err, NotNil)
+	_, err = pretendRead("*1\r\n:1\r\n").WriteReply(&errWriter{})
+	c.Assert(err, NotNil)
+	_, err = pretendRead("|1\r\n$1\r\na\r\n:1\r\n:5\r\n").WriteReply(&errWriter{})
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestRespFirstError(c *C) {
+	r := NewRespReader(bytes.NewBufferString(
+		"+OK\r\n" + "*2\r\n+OK\r\n$-1\r\n" +
+			"*3\r\n+OK\r\n*2\r\n:1\r\n-WRONGTYPE nested\r\n-ERR second\r\n" +
+			"-ERR error\r\n",
+	))
+
+	c.Assert(r.Read().FirstError(), IsNil)
+	c.Assert(r.Read().FirstError(), IsNil)
+	c.Assert(r.Read().FirstError(), ErrorMatches, "WRONGTYPE nested")
+	c.Assert(r.Read().FirstError(), ErrorMatches, "ERR error")
+	c.Assert(r.Read().FirstError(), NotNil)
+
+	var resp *Resp
+
+	c.Assert(resp.FirstError(), IsNil)
+}
+
+func (rs *RedySuite) TestRespErrPrefix(c *C) {
+	r := NewRespReader(bytes.NewBufferString("-WRONGTYPE Operation against a key\r\n-ERR\r\n+OK\r\n"))
+
+	c.Assert(r.Read().ErrPrefix(), Equals, "WRONGTYPE")
+	c.Assert(r.Read().ErrPrefix(), Equals, "ERR")
+	c.Assert(r.Read().ErrPrefix(), Equals, "")
+
+	var resp *Resp
+
+	c.Assert(resp.ErrPrefix(), Equals, "")
+}
+
+func (rs *RedySuite) TestRespInterface(c *C) {
+	r := NewRespReader(bytes.NewBufferString(
+		"*6\r\n+OK\r\n$4\r\ntest\r\n:-10\r\n$-1\r\n*1\r\n:1\r\n-ERR error\r\n",
+	))
+
+	v := r.Read().Interface()
+
+	c.Assert(v, HasLen, 6)
+
+	a := v.([]any)
+
+	c.Assert(a[:5], DeepEquals, []any{"OK", "test", int64(-10), nil, []any{int64(1)}})
+	c.Assert(a[5], ErrorMatches, "ERR error")
+
+	var resp *Resp
+
+	c.Assert(resp.Interface(), IsNil)
+	c.Assert(NewRespReader(&errReader{}).Read().Interface(), NotNil)
+}
+
+func (rs *RedySuite) TestRespPairs(c *C) {
+	r := NewRespReader(bytes.NewBufferString(
+		"*6\r\n$1\r\nz\r\n$1\r\n1\r\n$1\r\na\r\n$-1\r\n$1\r\nm\r\n+3\r\n" +
+			"*0\r\n" + "*1\r\n$1\r\na\r\n" + "*2\r\n:1\r\n$1\r\na\r\n" +
+			"*2\r\n$1\r\na\r\n:1\r\n" + "+OK\r\n" + "-ERR\r\n",
+	))
+
+	keys, values, err := r.Read().Pairs()
+	c.Assert(err, IsNil)
+	c.Assert(keys, DeepEquals, []string{"z", "a", "m"})
+	c.Assert(values, DeepEquals, []string{"1", "", "3"})
+
+	keys, values, err = r.Read().Pairs()
+	c.Assert(err, IsNil)
+	c.Assert(keys, HasLen, 0)
+	c.Assert(values, HasLen, 0)
+
+	_, _, err = r.Read().Pairs()
+	c.Assert(err, Equals, ErrNotMap)
+
+	_, _, err = r.Read().Pairs()
+	c.Assert(err, NotNil)
+
+	_, _, err = r.Read().Pairs()
+	c.Assert(err, NotNil)
+
+	_, _, err = r.Read().Pairs()
+	c.Assert(err, Equals, ErrNotArray)
+
+	_, _, err = r.Read().Pairs()
+	c.Assert(err, ErrorMatches, "ERR")
+}
+
+func (rs *RedySuite) TestRespTree(c *C) {
+	r := NewRespReader(bytes.NewBufferString(
+		"*6\r\n$6\r\nlength\r\n:2\r\n$6\r\ngroups\r\n*1\r\n*2\r\n$4\r\nname\r\n$2\r\ng1\r\n" +
+			"$4\r\nnone\r\n$-1\r\n" + "*1\r\n$1\r\na\r\n" + "*2\r\n:1\r\n$1\r\na\r\n" +
+			"+OK\r\n" + "-ERR\r\n",
+	))
+
+	m, err := r.Read().Tree()
+	c.Assert(err, IsNil)
+	c.Assert(m, DeepEquals, map[string]any{
+		"length": int64(2),
+		"groups": []any{[]any{"name", "g1"}},
+		"none":   nil,
+	})
+
+	_, err = r.Read().Tree()
+	c.Assert(err, Equals, ErrNotMap)
+
+	for i := 0; i < 3; i++ {
+		_, err = r.Read().Tree()
+		c.Assert(err, NotNil)
+	}
+}
+
+func (rs *RedySuite) TestRespMultiMap(c *C) {
+	r := NewRespReader(bytes.NewBufferString(
+		"*8\r\n$4\r\nsave\r\n*2\r\n$4\r\n3600\r\n$1\r\n1\r\n" +
+			"$4\r\nport\r\n$4\r\n6379\r\n$3\r\ndir\r\n+/tmp\r\n$4\r\nnone\r\n$-1\r\n" +
+			"*1\r\n$1\r\na\r\n" + "*2\r\n:1\r\n$1\r\na\r\n" +
+			"*2\r\n$1\r\na\r\n*1\r\n:1\r\n" + "+OK\r\n" + "-ERR\r\n",
+	))
+
+	m, err := r.Read().MultiMap()
+	c.Assert(err, IsNil)
+	c.Assert(m, DeepEquals, map[string][]string{
+		"save": {"3600", "1"},
+		"port": {"6379"},
+		"dir":  {"/tmp"},
+		"none": {},
+	})
+
+	_, err = r.Read().MultiMap()
+	c.Assert(err, Equals, ErrNotMap)
+
+	for i := 0; i < 4; i++ {
+		_, err = r.Read().MultiMap()
+		c.Assert(err, NotNil)
+	}
+}
+
+func (rs *RedySuite) TestRespAttributes(c *C) {
+	r := NewRespReader(bytes.NewBufferString(
+		"|2\r\n+key-popularity\r\n*2\r\n$1\r\na\r\n:10\r\n$3\r\nttl\r\n:100\r\n$4\r\ntest\r\n" +
+			"*2\r\n|1\r\n+a\r\n:1\r\n:2\r\n:3\r\n" +
+			"+OK\r\n" + "|1\r\n:1\r\n:1\r\n" + "|-1\r\n" + "|\r\n" + "|1\r\n+a\r\n",
+	))
+
+	resp := r.Read()
+	c.Assert(resp.Err, IsNil)
+	c.Assert(resp.String(), Equals, `Resp(BulkStr "test")`)
+	c.Assert(resp.Attributes(), HasLen, 2)
+	c.Assert(resp.Attributes()["key-popularity"].String(), Equals, `Resp(0:Resp(BulkStr "a") 1:Resp(Int 10))`)
+	c.Assert(resp.Attributes()["ttl"].String(), Equals, "Resp(Int 100)")
+
+	items, err := r.Read().Array()
+	c.Assert(err, IsNil)
+	c.Assert(items, HasLen, 2)
+	c.Assert(items[0].Attributes()["a"].String(), Equals, "Resp(Int 1)")
+	c.Assert(items[1].Attributes(), IsNil)
+
+	c.Assert(r.Read().Attributes(), IsNil)
+
+	c.Assert(r.Read().Err, Equals, ErrParse)
+	c.Assert(r.Read().Err, NotNil)
+	c.Assert(r.Read().Err, NotNil)
+	c.Assert(r.Read().Err, NotNil)
+
+	var nilResp *Resp
+
+	c.Assert(nilResp.Attributes(), IsNil)
+
+	rr := NewRespReader(bytes.NewBufferString("|1\r\n+a\r\n*1\r\n:1\r\n+OK\r\n"))
+	rr.MaxDepth = 1
+
+	c.Assert(rr.Read().Err, Equals, ErrRespTooDeep)
+
+	rr = NewRespReader(bytes.NewBufferString("|2\r\n"))
+	rr.MaxArrayLen = 3
+
+	c.Assert(rr.Read().Err, Equals, ErrRespTooBig)
+
+	rr = NewRespReader(bytes.NewBufferString("|4611686018427387904\r\n"))
+
+	c.Assert(rr.Read().Err, Equals, ErrRespTooBig)
+
+	rr = NewRespReader(bytes.NewBufferString(strings.Repeat("|0\r\n", 1000000) + "+OK\r\n"))
+	rr.MaxDepth = 5
+
+	c.Assert(rr.Read().Err, Equals, ErrParse)
+
+	rr = NewRespReader(bytes.NewBufferString("|1\r\n+a\r\n:1\r\n|0\r\n+OK\r\n"))
+
+	c.Assert(rr.Read().Err, Equals, ErrParse)
+}
+
+func (rs *RedySuite) TestRespDump(c *C) {
+	r := NewRespReader(bytes.NewBufferString(
+		"*5\r\n+OK\r\n*3\r\n$4\r\ntest\r\n:10\r\n*0\r\n$-1\r\n-ERR error\r\n:1\r\n",
+	))
+
+	c.Assert(r.Read().Dump(), Equals, `Array(5)
+  0: Str "OK"
+  1: Array(3)
+    0: BulkStr "test"
+    1: Int 10
+    2: Array(0)
+  2: Nil
+  3: RedisErr "ERR error"
+  4: Int 1
+`)
+
+	var resp *Resp
+
+	c.Assert(resp.Dump(), Equals, "")
+	c.Assert((&Resp{}).Dump(), Equals, "Unknown\n")
+}
+
+func (rs *RedySuite) TestRespTypeName(c *C) {
+	data := map[string]string{
+		"+OK\r\n": "Str", "$2\r\nOK\r\n": "BulkStr", "-ERR\r\n": "RedisErr",
+		":10\r\n": "Int", "$-1\r\n": "Nil", "*1\r\n:1\r\n": "Array",
+	}
+
+	for raw, name := range data {
+		r := NewRespReader(bytes.NewBufferString(raw)).Read()
+		c.Assert(r.TypeName(), Equals, name)
+	}
+
+	var r *Resp
+
+	c.Assert(r.TypeName(), Equals, "Unknown")
+	c.Assert((&Resp{}).TypeName(), Equals, "Unknown")
+	c.Assert(NewRespReader(&errReader{}).Read().TypeName(), Equals, "ErrIO")
+}
+
+func (rs *RedySuite) TestRespEqual(c *C) {
+	data := []string{
+		"+OK\r\n", "$2\r\nOK\r\n", "-ERR\r\n", ":10\r\n", "$-1\r\n",
+		"*2\r\n:1\r\n*1\r\n$1\r\nA\r\n", "*2\r\n:1\r\n*1\r\n$1\r\nB\r\n",
+		"*1\r\n:1\r\n", "*0\r\n",
+	}
+
+	for i, d1 := range data {
+		for j, d2 := range data {
+			c.Assert(pretendRead(d1).Equal(pretendRead(d2)), Equals, i == j)
+		}
+	}
+
+	var r *Resp
+
+	c.Assert(r.Equal(nil), Equals, true)
+	c.Assert(r.Equal(pretendRead("+OK\r\n")), Equals, false)
+	c.Assert(pretendRead("+OK\r\n").Equal(nil), Equals, false)
+	c.Assert((&Resp{typ: STR_BULK, val: 1}).Equal(&Resp{typ: STR_BULK, val: 1}), Equals, false)
+}
+
+func (rs *RedySuite) TestRespMaxDepth(c *C) {
+	data := "*1\r\n*1\r\n*1\r\n:1\r\n"
+
+	rr := NewRespReader(bytes.NewBufferString(data))
+	rr.MaxDepth = 3
+	r := rr.Read()
+	c.Assert(r.Err, IsNil)
+
+	rr = NewRespReader(bytes.NewBufferString(data))
+	rr.MaxDepth = 2
+	r = rr.Read()
+	c.Assert(r.HasType(ERR_IO), Equals, true)
+	c.Assert(r.Err, Equals, ErrRespTooDeep)
+
+	rr = NewRespReader(bytes.NewBufferString(data))
+	rr.MaxDepth = 2
+	rr.KeepRaw = true
+	r = rr.Read()
+	c.Assert(r.Err, Equals, ErrRespTooDeep)
+
+	rr = NewRespReader(bytes.NewBufferString(strings.Repeat("*1\r\n", 10000) + ":1\r\n"))
+	r = rr.Read()
+	c.Assert(r.Err, IsNil)
+}
+
+func (rs *RedySuite) TestRespMaxArrayLen(c *C) {
+	data := "*3\r\n:1\r\n:2\r\n:3\r\n"
+
+	rr := NewRespReader(bytes.NewBufferString(data))
+	rr.MaxArrayLen = 3
+	r := rr.Read()
+	c.Assert(r.Err, IsNil)
+
+	rr = NewRespReader(bytes.NewBufferString(data))
+	rr.MaxArrayLen = 2
+	r = rr.Read()
+	c.Assert(r.HasType(ERR_IO), Equals, true)
+	c.Assert(r.Err, Equals, ErrRespTooBig)
+
+	r = pretendRead("*2000000000\r\n")
+	c.Assert(r.Err, Equals, ErrRespTooBig)
+}
+
+func (rs *RedySuite) TestLargeReply(c *C) {
+	var sizes []int64
+
+	rc := newPipeClient(
+		"$3\r\nabc\r\n$4\r\nabcd\r\n*4\r\n:1\r\n:2\r\n$5\r\nabcde\r\n:4\r\n$10\r\nabcdefghij\r\n",
+	)
+	rc.LargeReplySize = 3
+	rc.OnLargeReply = func(size int64) { sizes = append(sizes, size) }
+
+	c.Assert(rc.Cmd("GET", "a").Err, IsNil)
+	c.Assert(sizes, HasLen, 0)
+	c.Assert(rc.Cmd("GET", "a").Err, IsNil)
+	c.Assert(sizes, DeepEquals, []int64{4})
+	c.Assert(rc.Cmd("LRANGE", "a", 0, -1).Err, IsNil)
+	c.Assert(sizes, DeepEquals, []int64{4, 4, 5})
 
-	// Test that two bulks in a row read correctly
-	r = pretendRead("*2\r\n$4\r\nTEST\r\n$4\r\n1234\r\n")
-	c.Assert(r.String(), Equals, "Resp(0:Resp(BulkStr \"TEST\") 1:Resp(BulkStr \"1234\"))")
-	c.Assert(r.HasType(ARRAY), Equals, true)
-	c.Assert(len(r.val.([]Resp)), Equals, 2)
-	c.Assert(r.val.([]Resp)[0].HasType(STR_BULK), Equals, true)
-	c.Assert(r.val.([]Resp)[0].val, DeepEquals, []byte("TEST"))
-	c.Assert(r.val.([]Resp)[1].HasType(STR_BULK), Equals, true)
-	c.Assert(r.val.([]Resp)[1].val, DeepEquals, []byte("1234"))
+	rc.OnLargeReply = nil
 
-	r = &Resp{}
-	c.Assert(r.String(), Equals, "Resp(Unknown)")
+	c.Assert(rc.Cmd("GET", "a").Err, IsNil)
+	c.Assert(sizes, HasLen, 3)
 
-	r = &Resp{typ: ERR_IO, Err: errors.New("IOERR")}
-	c.Assert(r.String(), Equals, "Resp(ErrIO \"IOERR\")")
+	rc.Close()
 }
 
 func (rs *RedySuite) TestReqEncoding(c *C) {
@@ -493,17 +3139,17 @@ func (rs *RedySuite) TestRespReadParseErrors(c *C) {
 
 	rd = bytes.NewBuffer(append(prefixBulk, '\n'))
 	br = bufio.NewReader(rd)
-	_, err = readBulkStr(br)
+	_, err = readBulkStr(br, respLimits{})
 	c.Assert(err, NotNil)
 
 	rd = bytes.NewBuffer(append(prefixArray, '\n'))
 	br = bufio.NewReader(rd)
-	_, err = readArray(br)
+	_, err = readArray(br, respLimits{}, 1)
 	c.Assert(err, NotNil)
 
 	rd = bytes.NewBuffer(append(prefixBulk, []byte("1000000000000000\n")...))
 	br = bufio.NewReader(rd)
-	_, err = readBulkStr(br)
+	_, err = readBulkStr(br, respLimits{})
 	c.Assert(err, NotNil)
 }
 
@@ -521,6 +3167,7 @@ func (rs *RedySuite) TestInfoParser(c *C) {
 
 	// Append fake info
 	info.Sections["Persistence"].Values["aof_enabled"] = "1"
+	info.Sections["Replication"].Values["connected_slaves"] = "2"
 	info.Sections["Replication"].Values["slave0"] = "ip=123.21.98.33,port=23477,state=online,offset=14177815,lag=351"
 	info.Sections["Replication"].Values["replica1"] = "ip=123.21.98.33,port=23477,state=online,offset=14177815,lag=351"
 
@@ -540,6 +3187,8 @@ func (rs *RedySuite) TestInfoParser(c *C) {
 	c.Assert(info.Is("server", "hz", float64(10)), Equals, true)
 	c.Assert(info.Is("replication", "repl_backlog_active", false), Equals, true)
 	c.Assert(info.Is("persistence", "aof_enabled", true), Equals, true)
+	c.Assert(info.Persistence(), NotNil)
+	c.Assert(info.Persistence().AOFEnabled, Equals, true)
 
 	replicaInfo := info.GetReplicaInfo(0)
 
@@ -600,6 +3249,446 @@ func (rs *RedySuite) TestInfoParser(c *C) {
 	c.Assert(info.GetU("", ""), Equals, uint64(0))
 }
 
+func (rs *RedySuite) TestReplicaInfoParser(c *C) {
+	info, err := parseRedisInfo("# Replication\r\nrole:master\r\nconnected_slaves:2\r\n" +
+		"slave0:ip=2001:db8::1,port=6379,state=online,offset=14177815,lag=1\r\n" +
+		"slave1:port=6380,lag=5,ip=10.0.0.2,state=wait_bgsave,offset=100\r\n",
+	)
+
+	c.Assert(err, IsNil)
+	c.Assert(info, NotNil)
+
+	replicaInfo := info.GetReplicaInfo(0)
+
+	c.Assert(replicaInfo, NotNil)
+	c.Assert(replicaInfo.IP, Equals, "2001:db8::1")
+	c.Assert(replicaInfo.Port, Equals, 6379)
+	c.Assert(replicaInfo.State, Equals, "online")
+	c.Assert(replicaInfo.Offset, Equals, int64(14177815))
+	c.Assert(replicaInfo.Lag, Equals, int64(1))
+
+	replicaInfo = info.GetReplicaInfo(1)
+
+	c.Assert(replicaInfo, NotNil)
+	c.Assert(replicaInfo.IP, Equals, "10.0.0.2")
+	c.Assert(replicaInfo.Port, Equals, 6380)
+	c.Assert(replicaInfo.State, Equals, "wait_bgsave")
+	c.Assert(replicaInfo.Offset, Equals, int64(100))
+	c.Assert(replicaInfo.Lag, Equals, int64(5))
+
+	c.Assert(info.ReplicaCount(), Equals, 2)
+	c.Assert(info.GetReplicaInfo(2), IsNil)
+	c.Assert(info.GetReplicaInfo(-1), IsNil)
+
+	c.Assert(info.Is("replication", "role", "master"), Equals, true)
+	c.Assert(info.Is("replication", "role", "MASTER"), Equals, true)
+	c.Assert(info.Is("replication", "role", "Master"), Equals, true)
+	c.Assert(info.Is("replication", "role", "slave"), Equals, false)
+	c.Assert(info.Is("replication", "unknown", "master"), Equals, false)
+	c.Assert(info.Is("replication", "role", []byte("Master")), Equals, true)
+	c.Assert(info.Is("replication", "role", int32(1)), Equals, false)
+
+	c.Assert(readNamedField("ip=127.0.0.1,port", "port"), Equals, "")
+	c.Assert(readNamedField("", "ip"), Equals, "")
+}
+
+func (rs *RedySuite) TestStreamEntriesParser(c *C) {
+	r := NewRespReader(bytes.NewBufferString(
+		"*2\r\n*2\r\n$3\r\n1-0\r\n*4\r\n$1\r\na\r\n$1\r\n1\r\n$1\r\nb\r\n$1\r\n2\r\n" +
+			"*2\r\n$3\r\n2-0\r\n*0\r\n" +
+			"*0\r\n" +
+			"+OK\r\n" +
+			"-ERR\r\n" +
+			"*1\r\n:1\r\n" +
+			"*1\r\n*1\r\n$3\r\n1-0\r\n" +
+			"*1\r\n*2\r\n:1\r\n*0\r\n" +
+			"*1\r\n*2\r\n$3\r\n1-0\r\n*1\r\n$1\r\na\r\n",
+	))
+
+	entries, err := ParseStreamEntries(r.Read())
+	c.Assert(err, IsNil)
+	c.Assert(entries, DeepEquals, []StreamEntry{
+		{ID: "1-0", Fields: map[string]string{"a": "1", "b": "2"}},
+		{ID: "2-0", Fields: map[string]string{}},
+	})
+
+	entries, err = ParseStreamEntries(r.Read())
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 0)
+
+	_, err = ParseStreamEntries(r.Read())
+	c.Assert(err, Equals, ErrWrongStreamResponse)
+
+	_, err = ParseStreamEntries(r.Read())
+	c.Assert(err, ErrorMatches, "ERR")
+
+	for i := 0; i < 4; i++ {
+		_, err = ParseStreamEntries(r.Read())
+		c.Assert(err, ErrorMatches, "Can't parse stream entry: .*")
+	}
+}
+
+func (rs *RedySuite) TestTimeParser(c *C) {
+	r := NewRespReader(bytes.NewBufferString(
+		"*2\r\n$10\r\n1700000000\r\n$6\r\n250000\r\n" + "*2\r\n:1\r\n:5\r\n" +
+			"-ERR\r\n" + "+OK\r\n" + "*1\r\n:1\r\n" + "*2\r\n:1\r\n$1\r\nA\r\n",
+	))
+
+	t, err := parseTime(r.Read())
+	c.Assert(err, IsNil)
+	c.Assert(t.Equal(time.Unix(1700000000, 250000000)), Equals, true)
+
+	t, err = parseTime(r.Read())
+	c.Assert(err, IsNil)
+	c.Assert(t.Equal(time.Unix(1, 5000)), Equals, true)
+
+	_, err = parseTime(r.Read())
+	c.Assert(err, ErrorMatches, "ERR")
+
+	for i := 0; i < 3; i++ {
+		_, err = parseTime(r.Read())
+		c.Assert(err, Equals, ErrWrongTimeResponse)
+	}
+}
+
+func (rs *RedySuite) TestHelloParser(c *C) {
+	r := NewRespReader(bytes.NewBufferString(
+		"*14\r\n$6\r\nserver\r\n$5\r\nredis\r\n$7\r\nversion\r\n$5\r\n7.2.4\r\n" +
+			"$5\r\nproto\r\n:2\r\n$2\r\nid\r\n:15\r\n$4\r\nmode\r\n$10\r\nstandalone\r\n" +
+			"$4\r\nrole\r\n$6\r\nmaster\r\n$7\r\nmodules\r\n" +
+			"*1\r\n*4\r\n$4\r\nname\r\n$6\r\nsearch\r\n$3\r\nver\r\n:20811\r\n" +
+			"*0\r\n" + "+OK\r\n" + "-ERR\r\n" + "*1\r\n$1\r\na\r\n" +
+			"*2\r\n:1\r\n:1\r\n" + "*2\r\n$5\r\nproto\r\n$1\r\nA\r\n" +
+			"*2\r\n$7\r\nmodules\r\n*1\r\n:1\r\n" +
+			"*2\r\n$7\r\nmodules\r\n*1\r\n*2\r\n$4\r\nname\r\n:1\r\n",
+	))
+
+	hello, err := ParseHello(r.Read())
+	c.Assert(err, IsNil)
+	c.Assert(hello, DeepEquals, &ServerHello{
+		Server: "redis", Version: "7.2.4", Proto: 2, ID: 15,
+		Mode: "standalone", Role: "master", Modules: []string{"search"},
+	})
+
+	hello, err = ParseHello(r.Read())
+	c.Assert(err, IsNil)
+	c.Assert(hello, DeepEquals, &ServerHello{})
+
+	_, err = ParseHello(r.Read())
+	c.Assert(err, Equals, ErrWrongHelloResponse)
+
+	_, err = ParseHello(r.Read())
+	c.Assert(err, ErrorMatches, "ERR")
+
+	for i := 0; i < 5; i++ {
+		_, err = ParseHello(r.Read())
+		c.Assert(err, ErrorMatches, "Can't parse HELLO data.*")
+	}
+}
+
+func (rs *RedySuite) TestInfoStringParser(c *C) {
+	info, err := ParseInfoString("# Server\r\nredis_version:7.2.4\r\n")
+
+	c.Assert(err, IsNil)
+	c.Assert(info.Get("server", "redis_version"), Equals, "7.2.4")
+
+	section := info.Section("SERVER")
+
+	c.Assert(section, NotNil)
+	c.Assert(section.Header, Equals, "Server")
+	c.Assert(section.Fields, DeepEquals, []string{"redis_version"})
+	c.Assert(section.Values["redis_version"], Equals, "7.2.4")
+	c.Assert(info.Section("unknown"), IsNil)
+
+	info = nil
+
+	c.Assert(info.Section("server"), IsNil)
+
+	_, err = ParseInfoString("")
+	c.Assert(err, ErrorMatches, "Can't parse INFO data: INFO data is empty")
+}
+
+func (rs *RedySuite) TestInfoBoolValues(c *C) {
+	info, err := ParseInfoString(
+		"# Test\r\n" +
+			"t1:1\r\nt2:ok\r\nt3:yes\r\nt4:TRUE\r\nt5:up\r\nt6:Enabled\r\n" +
+			"f1:0\r\nf2:no\r\nf3:false\r\nf4:DOWN\r\nf5:disabled\r\nf6:\r\n",
+	)
+
+	c.Assert(err, IsNil)
+
+	for i := 1; i <= 6; i++ {
+		c.Assert(info.GetB("test", "t"+strconv.Itoa(i)), Equals, true)
+		c.Assert(info.GetB("test", "f"+strconv.Itoa(i)), Equals, false)
+	}
+
+	c.Assert(info.GetB("test", "unknown"), Equals, false)
+}
+
+func (rs *RedySuite) TestHitRatioDelta(c *C) {
+	statsInfo := func(hits, misses int) *Info {
+		info, err := ParseInfoString(fmt.Sprintf(
+			"# Stats\r\nkeyspace_hits:%d\r\nkeyspace_misses:%d\r\n", hits, misses,
+		))
+
+		c.Assert(err, IsNil)
+
+		return info
+	}
+
+	c.Assert(HitRatioDelta(statsInfo(100, 100), statsInfo(130, 110)), Equals, 0.75)
+	c.Assert(HitRatioDelta(statsInfo(100, 100), statsInfo(100, 100)), Equals, 0.0)
+	c.Assert(math.IsNaN(HitRatioDelta(statsInfo(100, 100), statsInfo(10, 200))), Equals, true)
+	c.Assert(math.IsNaN(HitRatioDelta(statsInfo(100, 100), statsInfo(200, 10))), Equals, true)
+	c.Assert(math.IsNaN(HitRatioDelta(nil, statsInfo(1, 1))), Equals, true)
+}
+
+func (rs *RedySuite) TestInfoSectionsParser(c *C) {
+	var info *Info
+
+	c.Assert(info.Persistence(), IsNil)
+	c.Assert(info.Clients(), IsNil)
+	c.Assert(info.Cluster(), IsNil)
+	c.Assert(info.ClusterEnabled(), Equals, false)
+
+	info, err := parseRedisInfo("# Server\r\nredis_version:7.2.4\r\n")
+
+	c.Assert(err, IsNil)
+	c.Assert(info.Persistence(), IsNil)
+	c.Assert(info.Clients(), IsNil)
+	c.Assert(info.ClusterEnabled(), Equals, false)
+
+	info, err = parseRedisInfo("# Cluster\r\ncluster_enabled:1\r\n")
+
+	c.Assert(err, IsNil)
+	c.Assert(info.Cluster(), DeepEquals, &ClusterInfo{Enabled: true})
+	c.Assert(info.ClusterEnabled(), Equals, true)
+
+	info, err = parseRedisInfo("# Cluster\r\ncluster_enabled:0\r\n")
+
+	c.Assert(err, IsNil)
+	c.Assert(info.ClusterEnabled(), Equals, false)
+
+	info, err = parseRedisInfo(
+		"# Clients\r\nconnected_clients:12\r\ncluster_connections:2\r\n" +
+			"maxclients:10000\r\nclient_recent_max_input_buffer:20480\r\n" +
+			"client_recent_max_output_buffer:4096\r\nblocked_clients:3\r\n" +
+			"tracking_clients:1\r\nclients_in_timeout_table:3\r\n",
+	)
+
+	c.Assert(err, IsNil)
+	c.Assert(info.Clients(), DeepEquals, &ClientsInfo{
+		ConnectedClients:            12,
+		ClusterConnections:          2,
+		MaxClients:                  10000,
+		BlockedClients:              3,
+		TrackingClients:             1,
+		ClientsInTimeoutTable:       3,
+		ClientRecentMaxInputBuffer:  20480,
+		ClientRecentMaxOutputBuffer: 4096,
+	})
+
+	info, err = parseRedisInfo(
+		"# Persistence\r\nloading:0\r\nrdb_changes_since_last_save:129\r\n" +
+			"rdb_bgsave_in_progress:1\r\nrdb_last_save_time:1700000000\r\n" +
+			"aof_enabled:1\r\naof_rewrite_in_progress:0\r\n",
+	)
+
+	c.Assert(err, IsNil)
+
+	persistence := info.Persistence()
+
+	c.Assert(persistence, NotNil)
+	c.Assert(persistence.RDBLastSaveTime.Unix(), Equals, int64(1700000000))
+	c.Assert(persistence.RDBChangesSinceLastSave, Equals, int64(129))
+	c.Assert(persistence.RDBBgsaveInProgress, Equals, true)
+	c.Assert(persistence.AOFEnabled, Equals, true)
+	c.Assert(persistence.AOFRewriteInProgress, Equals, false)
+	c.Assert(persistence.LoadingInProgress, Equals, false)
+	c.Assert(info.CPU(), IsNil)
+
+	info, err = parseRedisInfo(
+		"# CPU\r\nused_cpu_sys:12.5\r\nused_cpu_user:30.25\r\n" +
+			"used_cpu_sys_children:0.5\r\nused_cpu_user_children:1.75\r\n",
+	)
+
+	c.Assert(err, IsNil)
+
+	cpu := info.CPU()
+
+	c.Assert(cpu, NotNil)
+	c.Assert(cpu.UsedCPUSys, Equals, 12.5)
+	c.Assert(cpu.UsedCPUUser, Equals, 30.25)
+	c.Assert(cpu.UsedCPUSysChildren, Equals, 0.5)
+	c.Assert(cpu.UsedCPUUserChildren, Equals, 1.75)
+	c.Assert(info.Stats(), IsNil)
+
+	var stats *StatsInfo
+
+	c.Assert(stats.HitRatio(), Equals, 0.0)
+
+	info, err = parseRedisInfo(
+		"# Stats\r\ntotal_connections_received:120\r\ntotal_commands_processed:5400\r\n" +
+			"instantaneous_ops_per_sec:17\r\nrejected_connections:2\r\nexpired_keys:40\r\n" +
+			"evicted_keys:3\r\nkeyspace_hits:300\r\nkeyspace_misses:100\r\n",
+	)
+
+	c.Assert(err, IsNil)
+
+	stats = info.Stats()
+
+	c.Assert(stats, NotNil)
+	c.Assert(stats.TotalConnectionsReceived, Equals, uint64(120))
+	c.Assert(stats.TotalCommandsProcessed, Equals, uint64(5400))
+	c.Assert(stats.InstantaneousOpsPerSec, Equals, uint64(17))
+	c.Assert(stats.KeyspaceHits, Equals, uint64(300))
+	c.Assert(stats.KeyspaceMisses, Equals, uint64(100))
+	c.Assert(stats.ExpiredKeys, Equals, uint64(40))
+	c.Assert(stats.EvictedKeys, Equals, uint64(3))
+	c.Assert(stats.RejectedConnections, Equals, uint64(2))
+	c.Assert(stats.HitRatio(), Equals, 0.75)
+
+	stats.KeyspaceHits, stats.KeyspaceMisses = 0, 0
+	c.Assert(stats.HitRatio(), Equals, 0.0)
+}
+
+func (rs *RedySuite) TestCommandInfoParser(c *C) {
+	r := pretendRead("*3\r\n" +
+		"*10\r\n$3\r\nget\r\n:2\r\n*2\r\n+readonly\r\n+fast\r\n:1\r\n:1\r\n:1\r\n" +
+		"*2\r\n+@read\r\n+@string\r\n*0\r\n*0\r\n*0\r\n" +
+		"*-1\r\n" +
+		"*6\r\n$4\r\nmset\r\n:-3\r\n*2\r\n+write\r\n+denyoom\r\n:1\r\n:-1\r\n:2\r\n",
+	)
+
+	specs, err := ParseCommandInfo(r)
+
+	c.Assert(err, IsNil)
+	c.Assert(specs, HasLen, 2)
+	c.Assert(specs[0], DeepEquals, CommandSpec{
+		Name: "get", Arity: 2, Flags: []string{"readonly", "fast"},
+		FirstKey: 1, LastKey: 1, Step: 1,
+	})
+	c.Assert(specs[1], DeepEquals, CommandSpec{
+		Name: "mset", Arity: -3, Flags: []string{"write", "denyoom"},
+		FirstKey: 1, LastKey: -1, Step: 2,
+	})
+
+	_, err = ParseCommandInfo(pretendRead("+OK\r\n"))
+	c.Assert(err, Equals, ErrWrongCommandResponse)
+
+	_, err = ParseCommandInfo(pretendRead("*1\r\n*2\r\n$3\r\nget\r\n:2\r\n"))
+	c.Assert(err, NotNil)
+
+	_, err = ParseCommandInfo(pretendRead("*1\r\n*6\r\n$3\r\nget\r\n+A\r\n*0\r\n:1\r\n:1\r\n:1\r\n"))
+	c.Assert(err, NotNil)
+
+	_, err = ParseCommandInfo(pretendRead("*1\r\n*6\r\n$3\r\nget\r\n:2\r\n:1\r\n:1\r\n:1\r\n:1\r\n"))
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestACLUserParser(c *C) {
+	r := pretendRead("*12\r\n" +
+		"$5\r\nflags\r\n*2\r\n$2\r\non\r\n$8\r\nsanitize\r\n" +
+		"$9\r\npasswords\r\n*1\r\n$4\r\nabcd\r\n" +
+		"$8\r\ncommands\r\n$12\r\n+@all -debug\r\n" +
+		"$4\r\nkeys\r\n$11\r\n~app:* %R~*\r\n" +
+		"$8\r\nchannels\r\n$2\r\n&*\r\n" +
+		"$9\r\nselectors\r\n*1\r\n*6\r\n" +
+		"$8\r\ncommands\r\n$5\r\n+@get\r\n" +
+		"$4\r\nkeys\r\n$7\r\n~cache*\r\n" +
+		"$8\r\nchannels\r\n$0\r\n\r\n",
+	)
+
+	user, err := ParseACLUser(r)
+
+	c.Assert(err, IsNil)
+	c.Assert(user, NotNil)
+	c.Assert(user.Flags, DeepEquals, []string{"on", "sanitize"})
+	c.Assert(user.Passwords, DeepEquals, []string{"abcd"})
+	c.Assert(user.Commands, Equals, "+@all -debug")
+	c.Assert(user.Keys, DeepEquals, []string{"~app:*", "%R~*"})
+	c.Assert(user.Channels, DeepEquals, []string{"&*"})
+	c.Assert(user.Selectors, HasLen, 1)
+	c.Assert(user.Selectors[0].Commands, Equals, "+@get")
+	c.Assert(user.Selectors[0].Keys, DeepEquals, []string{"~cache*"})
+	c.Assert(user.Selectors[0].Channels, HasLen, 0)
+
+	// Redis 6 format
+	r = pretendRead("*4\r\n" +
+		"$4\r\nkeys\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n" +
+		"$8\r\nchannels\r\n*1\r\n$1\r\n*\r\n",
+	)
+
+	user, err = ParseACLUser(r)
+
+	c.Assert(err, IsNil)
+	c.Assert(user.Keys, DeepEquals, []string{"a", "b"})
+	c.Assert(user.Channels, DeepEquals, []string{"*"})
+
+	_, err = ParseACLUser(pretendRead("*-1\r\n"))
+	c.Assert(err, Equals, ErrRespNil)
+
+	_, err = ParseACLUser(pretendRead("+OK\r\n"))
+	c.Assert(err, Equals, ErrWrongACLResponse)
+
+	_, err = ParseACLUser(pretendRead("*1\r\n$5\r\nflags\r\n"))
+	c.Assert(err, Equals, ErrNotMap)
+
+	_, err = ParseACLUser(pretendRead("*2\r\n$5\r\nflags\r\n:1\r\n"))
+	c.Assert(err, NotNil)
+
+	_, err = ParseACLUser(pretendRead("*2\r\n$9\r\nselectors\r\n*1\r\n*1\r\n:1\r\n"))
+	c.Assert(err, NotNil)
+}
+
+func (rs *RedySuite) TestLatencyParsers(c *C) {
+	r := pretendRead("*2\r\n" +
+		"*4\r\n$7\r\ncommand\r\n:1700000000\r\n:250\r\n:1000\r\n" +
+		"*4\r\n$12\r\nfast-command\r\n:1700000100\r\n:5\r\n:12\r\n",
+	)
+
+	events, err := ParseLatencyLatest(r)
+
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 2)
+	c.Assert(events[0].Event, Equals, "command")
+	c.Assert(events[0].Time.Unix(), Equals, int64(1700000000))
+	c.Assert(events[0].Latest, Equals, 250*time.Millisecond)
+	c.Assert(events[0].Max, Equals, time.Second)
+	c.Assert(events[1].Event, Equals, "fast-command")
+
+	r = pretendRead("*2\r\n*2\r\n:1700000000\r\n:250\r\n*2\r\n:1700000010\r\n:1000\r\n")
+
+	samples, err := ParseLatencyHistory(r)
+
+	c.Assert(err, IsNil)
+	c.Assert(samples, HasLen, 2)
+	c.Assert(samples[0].Time.Unix(), Equals, int64(1700000000))
+	c.Assert(samples[0].Latency, Equals, 250*time.Millisecond)
+	c.Assert(samples[1].Time.Unix(), Equals, int64(1700000010))
+	c.Assert(samples[1].Latency, Equals, time.Second)
+
+	_, err = ParseLatencyLatest(pretendRead("+OK\r\n"))
+	c.Assert(err, Equals, ErrWrongLatencyResponse)
+	_, err = ParseLatencyLatest(pretendRead("*1\r\n:1\r\n"))
+	c.Assert(err, NotNil)
+	_, err = ParseLatencyLatest(pretendRead("*1\r\n*1\r\n:1\r\n"))
+	c.Assert(err, NotNil)
+	_, err = ParseLatencyLatest(pretendRead("*1\r\n*4\r\n:1\r\n:1\r\n:1\r\n:1\r\n"))
+	c.Assert(err, NotNil)
+	_, err = ParseLatencyLatest(pretendRead("*1\r\n*4\r\n+A\r\n+B\r\n:1\r\n:1\r\n"))
+	c.Assert(err, NotNil)
+
+	_, err = ParseLatencyHistory(pretendRead("+OK\r\n"))
+	c.Assert(err, Equals, ErrWrongLatencyResponse)
+	_, err = ParseLatencyHistory(pretendRead("*1\r\n:1\r\n"))
+	c.Assert(err, NotNil)
+	_, err = ParseLatencyHistory(pretendRead("*1\r\n*1\r\n:1\r\n"))
+	c.Assert(err, NotNil)
+	_, err = ParseLatencyHistory(pretendRead("*1\r\n*2\r\n+A\r\n:1\r\n"))
+	c.Assert(err, NotNil)
+}
+
 func (rs *RedySuite) TestConfigParsers(c *C) {
 	var cfg *Config
 
@@ -650,13 +3739,43 @@ func (rs *RedySuite) TestConfigParsers(c *C) {
 	c.Assert(memConf.Get("save"), Equals, fcSave)
 	c.Assert(memConf.Get("client-output-buffer-limit"), Equals, fcLimit)
 
+	memConf, err = rs.c.GetConfigMatching("CONFIG", "tcp-*")
+
+	c.Assert(err, IsNil)
+	c.Assert(memConf, NotNil)
+	c.Assert(memConf.Get("tcp-keepalive"), Equals, fcKeepalive)
+	c.Assert(memConf.Has("save"), Equals, false)
+
+	memConf, err = rs.c.GetConfigMatching("CONFIG")
+
+	c.Assert(err, IsNil)
+	c.Assert(memConf.Get("save"), Equals, fcSave)
+
 	resp := &Resp{typ: STR_SIMPLE, val: ""}
 	_, err = parseInMemoryConfig(resp)
 	c.Assert(err, NotNil)
 
 	resp = &Resp{typ: ARRAY, val: []Resp{Resp{}, Resp{}, Resp{}}}
 	_, err = parseInMemoryConfig(resp)
-	c.Assert(err, NotNil)
+	c.Assert(err, Equals, ErrWrongConfigItems)
+}
+
+func (rs *RedySuite) TestInMemoryConfigParser(c *C) {
+	r := NewRespReader(bytes.NewBufferString(
+		"*4\r\n$11\r\nrequirepass\r\n$4\r\na\x00\x01\xff\r\n$4\r\ndir1\r\n$-1\r\n" +
+			"*2\r\n:1\r\n$1\r\na\r\n" + "*2\r\n$3\r\ndir\r\n*0\r\n",
+	))
+
+	memConf, err := parseInMemoryConfig(r.Read())
+	c.Assert(err, IsNil)
+	c.Assert(memConf.Get("requirepass"), Equals, "a\x00\x01\xff")
+	c.Assert(memConf.Has("dir1"), Equals, true)
+	c.Assert(memConf.Get("dir1"), Equals, "")
+
+	_, err = parseInMemoryConfig(r.Read())
+	c.Assert(err, ErrorMatches, "Can't parse CONFIG property name: .*")
+	_, err = parseInMemoryConfig(r.Read())
+	c.Assert(err, ErrorMatches, `Can't parse CONFIG property "dir" value: .*`)
 }
 
 func (rs *RedySuite) TestConfigDiff(c *C) {
@@ -716,6 +3835,28 @@ func (rs *RedySuite) TestFlatten(c *C) {
 	c.Assert(flattenedLength(fl), Equals, 1)
 }
 
+func (rs *RedySuite) TestSliceEncoding(c *C) {
+	var buf bytes.Buffer
+
+	c.Assert(flattenedLength([]string{"a", "b"}, [][]byte{[]byte("c")}), Equals, 3)
+
+	_, err := writeTo(&buf, nil, []string{"a", "bc"})
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Equals, "$1\r\na\r\n$2\r\nbc\r\n")
+
+	buf.Reset()
+
+	_, err = writeTo(&buf, nil, [][]byte{[]byte("a"), nil})
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Equals, "$1\r\na\r\n$0\r\n\r\n")
+
+	_, err = writeTo(&errWriter{}, nil, []string{"a"})
+	c.Assert(err, NotNil)
+
+	_, err = writeTo(&errWriter{}, nil, [][]byte{[]byte("a")})
+	c.Assert(err, NotNil)
+}
+
 func (rs *RedySuite) TestRead(c *C) {
 	r := bufio.NewReader(&errReader{})
 
@@ -728,10 +3869,10 @@ func (rs *RedySuite) TestRead(c *C) {
 	_, err = readInt(r)
 	c.Assert(err, NotNil)
 
-	_, err = readBulkStr(r)
+	_, err = readBulkStr(r, respLimits{})
 	c.Assert(err, NotNil)
 
-	_, err = readArray(r)
+	_, err = readArray(r, respLimits{}, 1)
 	c.Assert(err, NotNil)
 }
 
@@ -817,6 +3958,58 @@ func (rs *RedySuite) TestAux(c *C) {
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+func BenchmarkWriteStrSlice(b *testing.B) {
+	args := make([]string, 1000)
+
+	for i := range args {
+		args[i] = randString(16)
+	}
+
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		writeTo(io.Discard, buf, args)
+	}
+}
+
+// BenchmarkWriteStrSliceReflect encodes the same input using reflection-based
+// path which was used for []string before the fast path
+func BenchmarkWriteStrSliceReflect(b *testing.B) {
+	args := make([]string, 1000)
+
+	for i := range args {
+		args[i] = randString(16)
+	}
+
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		writeSlice(io.Discard, buf, args)
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// newPipeClient creates client connected to in-memory pipe which reads
+// replies from the given script and discards all sent commands
+func newPipeClient(script string) *Client {
+	conn, srv := net.Pipe()
+
+	go io.Copy(io.Discard, srv)
+
+	return &Client{
+		conn:       conn,
+		respReader: NewRespReader(bytes.NewBufferString(script)),
+		writeBuf:   &bytes.Buffer{},
+	}
+}
+
 func pretendRead(s string) *Resp {
 	buf := bytes.NewBufferString(s)
 	return NewRespReader(buf).Read()
@@ -854,3 +4047,81 @@ func (t *timeoutError) Error() string {
 func (r *errReader) Read(p []byte) (n int, err error) {
 	return 0, errors.New("ERROR")
 }
+
+func (w *errWriter) Write(p []byte) (n int, err error) {
+	return 0, errors.New("ERROR")
+}
+
+func (c *deadlineConn) SetReadDeadline(t time.Time) error {
+	c.readDeadline = true
+	return c.Conn.SetReadDeadline(t)
+}
+
+func (c *deadlineConn) SetWriteDeadline(t time.Time) error {
+	c.writeDeadline = true
+	return c.Conn.SetWriteDeadline(t)
+}
+
+func (c *cancelConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.cancel()
+	return n, err
+}
+
+func BenchmarkCmdLatency(b *testing.B) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	defer ln.Close()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+
+			if err != nil {
+				return
+			}
+
+			go func() {
+				buf := make([]byte, 64)
+
+				for {
+					_, err := conn.Read(buf)
+
+					if err != nil {
+						return
+					}
+
+					conn.Write([]byte("+PONG\r\n"))
+				}
+			}()
+		}
+	}()
+
+	for _, disable := range []bool{false, true} {
+		name := "NoDelay"
+
+		if disable {
+			name = "Nagle"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			rc := &Client{Addr: ln.Addr().String(), DisableNoDelay: disable}
+
+			if rc.Connect() != nil {
+				b.Fatal("Can't connect to server")
+			}
+
+			defer rc.Close()
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				rc.Cmd("PING")
+			}
+		})
+	}
+}
@@ -4,12 +4,16 @@ package redy
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -42,28 +46,72 @@ type RespType uint8
 type Resp struct {
 	Err error
 
-	val any
-	typ RespType
+	val   any
+	typ   RespType
+	raw   []byte
+	attrs map[string]*Resp
 }
 
 // RespReader is a wrapper around an io.Reader which will read Resp messages off
 // of the io.Reader
 type RespReader struct {
+	// KeepRaw enables capturing of raw reply bytes (see Resp.Raw)
+	KeepRaw bool
+
+	// MaxDepth is maximum nesting depth of arrays (0 = unlimited)
+	MaxDepth int
+
+	// MaxArrayLen is maximum number of array elements (0 = 512M elements)
+	MaxArrayLen int64
+
+	// LargeReplySize is soft limit for size of bulk strings (in bytes) and
+	// arrays (in elements) exceeding which OnLargeReply is called (0 = disabled)
+	LargeReplySize int64
+
+	// OnLargeReply is callback which is called if bulk string or array size
+	// exceeds LargeReplySize
+	OnLargeReply func(size int64)
+
 	r *bufio.Reader
 }
 
+// respLimits contains limits used by RESP parser
+type respLimits struct {
+	maxDepth       int
+	maxArrayLen    int64
+	largeReplySize int64
+	onLargeReply   func(size int64)
+}
+
+// respReader is an interface for buffered reader used by RESP parser
+type respReader interface {
+	Peek(n int) ([]byte, error)
+	ReadBytes(delim byte) ([]byte, error)
+	Read(p []byte) (int, error)
+	ReadByte() (byte, error)
+}
+
+// rawRecorder is buffered reader which records all consumed data
+type rawRecorder struct {
+	r   *bufio.Reader
+	raw []byte
+}
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 // Errors
 var (
-	ErrBadType    = errors.New("Wrong type")
-	ErrParse      = errors.New("Parse error")
-	ErrNotStr     = errors.New("Couldn't convert response to string")
-	ErrNotInt     = errors.New("Couldn't convert response to int")
-	ErrNotArray   = errors.New("Couldn't convert response to array")
-	ErrNotMap     = errors.New("Couldn't convert response to map (reply has odd number of elements)")
-	ErrRespNil    = errors.New("Response is nil")
-	ErrRespTooBig = errors.New("Response is huge and can't be parsed")
+	ErrBadType     = errors.New("Wrong type")
+	ErrParse       = errors.New("Parse error")
+	ErrNotStr      = errors.New("Couldn't convert response to string")
+	ErrNotInt      = errors.New("Couldn't convert response to int")
+	ErrNotArray    = errors.New("Couldn't convert response to array")
+	ErrNotMap      = errors.New("Couldn't convert response to map (reply has odd number of elements)")
+	ErrRespNil     = errors.New("Response is nil")
+	ErrRespTooBig  = errors.New("Response is huge and can't be parsed")
+	ErrNotOK       = errors.New("Response is not OK")
+	ErrRespTooDeep = errors.New("Response has too many nesting levels")
+	ErrBadArgType  = errors.New("Unsupported argument type")
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -79,11 +127,15 @@ var (
 	prefixInt    = []byte{':'}
 	prefixBulk   = []byte{'$'}
 	prefixArray  = []byte{'*'}
+	prefixAttr   = []byte{'|'}
 	nilFormatted = []byte("$-1\r\n")
 )
 
 var maxInt = int(^uint(0) >> 1)
 
+// defaultMaxArrayLen is default maximum number of array elements
+const defaultMaxArrayLen = 512 * 1024 * 1024
+
 var typeOfBytes = reflect.TypeOf([]byte(nil))
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -97,13 +149,17 @@ func NewRespReader(r io.Reader) *RespReader {
 		br = bufio.NewReader(r)
 	}
 
-	return &RespReader{br}
+	return &RespReader{r: br}
 }
 
 // Read attempts to read a message object from the given io.Reader, parse
 // it, and return a Resp representing it
 func (r *RespReader) Read() *Resp {
-	resp, err := bufioReadResp(r.r)
+	if r.KeepRaw {
+		return r.readWithRaw()
+	}
+
+	resp, err := readResp(r.r, r.limits(), 1)
 
 	if err != nil {
 		resp = errToResp(ERR_IO, err)
@@ -199,6 +255,10 @@ func (r *Resp) Float64() (float64, error) {
 		return 0.0, r.Err
 	}
 
+	if i, ok := r.val.(int64); ok {
+		return float64(i), nil
+	}
+
 	b, ok := r.val.([]byte)
 
 	if !ok {
@@ -236,6 +296,60 @@ func (r *Resp) Array() ([]*Resp, error) {
 	return ac, nil
 }
 
+// ForEach calls given function for every element of the array reply without
+// allocating a new slice. Iteration stops on the first error returned by the
+// function, and this error is returned.
+func (r *Resp) ForEach(fn func(i int, r *Resp) error) error {
+	if r.Err != nil {
+		return r.Err
+	}
+
+	a, ok := r.val.([]Resp)
+
+	if !ok {
+		return ErrNotArray
+	}
+
+	for i := range a {
+		err := fn(i, &a[i])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Interface recursively converts the reply into native Go types: string for
+// simple and bulk strings, int64 for integers, []any for arrays, nil for NIL
+// and error for errors
+func (r *Resp) Interface() any {
+	if r == nil {
+		return nil
+	}
+
+	switch r.typ {
+	case STR_SIMPLE, STR_BULK:
+		return string(r.val.([]byte))
+	case INT:
+		return r.val.(int64)
+	case ARRAY:
+		a := r.val.([]Resp)
+		result := make([]any, len(a))
+
+		for i := range a {
+			result[i] = a[i].Interface()
+		}
+
+		return result
+	case ERR_IO, ERR_REDIS:
+		return r.Err
+	}
+
+	return nil
+}
+
 // List is a wrapper around Array which returns the result as a list of strings,
 // calling Str() on each Resp which Array returns. Any errors encountered are
 // immediately returned. Any Nil replies are interpreted as empty strings
@@ -304,6 +418,44 @@ func (r *Resp) ListBytes() ([][]byte, error) {
 	return list, nil
 }
 
+// ListWithNils is a wrapper around Array which returns the result as a list of
+// string pointers. Unlike List, any Nil replies are interpreted as nil pointers,
+// so missing values can be distinguished from empty strings.
+func (r *Resp) ListWithNils() ([]*string, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	a, ok := r.val.([]Resp)
+
+	if !ok {
+		return nil, ErrNotArray
+	}
+
+	list := make([]*string, len(a))
+
+	for i := range a {
+		if a[i].HasType(NIL) {
+			continue
+		}
+
+		s, err := a[i].Str()
+
+		if err != nil {
+			return nil, err
+		}
+
+		list[i] = &s
+	}
+
+	return list, nil
+}
+
+// Strings is an alias for List
+func (r *Resp) Strings() ([]string, error) {
+	return r.List()
+}
+
 // Map is a wrapper around Array which returns the result as a map of strings,
 // calling Str() on alternating key/values for the map. All value fields of type
 // Nil will be treated as empty strings, keys must all be of type Str
@@ -353,6 +505,136 @@ func (r *Resp) Map() (map[string]string, error) {
 	}
 }
 
+// Tree returns key/value array reply as a map where values are decoded using
+// Interface. Since RESP2 has no separate map type, nested maps can't be
+// distinguished from arrays, so they are returned as []any with alternating
+// keys and values. Keys must all be of type Str.
+func (r *Resp) Tree() (map[string]any, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	a, ok := r.val.([]Resp)
+
+	if !ok {
+		return nil, ErrNotArray
+	}
+
+	if len(a)%2 != 0 {
+		return nil, ErrNotMap
+	}
+
+	m := make(map[string]any, len(a)/2)
+
+	for i := 0; i < len(a); i += 2 {
+		k, err := a[i].Str()
+
+		if err != nil {
+			return nil, err
+		}
+
+		m[k] = a[i+1].Interface()
+	}
+
+	return m, nil
+}
+
+// MultiMap is a wrapper around Array which returns the result as a map of string
+// slices, calling Str() on alternating key/values for the map. Values can be
+// arrays or single strings, single values are returned as one-element slices.
+// All value fields of type Nil will be treated as empty slices, keys must all be
+// of type Str.
+func (r *Resp) MultiMap() (map[string][]string, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	a, ok := r.val.([]Resp)
+
+	if !ok {
+		return nil, ErrNotArray
+	}
+
+	if len(a)%2 != 0 {
+		return nil, ErrNotMap
+	}
+
+	m := make(map[string][]string)
+
+	for i := 0; i < len(a); i += 2 {
+		k, v := a[i], a[i+1]
+
+		ks, err := k.Str()
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case v.HasType(NIL):
+			m[ks] = []string{}
+			continue
+		case v.HasType(ARRAY):
+			m[ks], err = v.List()
+		default:
+			var vs string
+			vs, err = v.Str()
+			m[ks] = []string{vs}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Pairs is a wrapper around Array which returns keys and values from
+// alternating key/values of the array preserving their order. All value fields
+// of type Nil will be treated as empty strings, keys must all be of type Str
+func (r *Resp) Pairs() ([]string, []string, error) {
+	if r.Err != nil {
+		return nil, nil, r.Err
+	}
+
+	a, ok := r.val.([]Resp)
+
+	if !ok {
+		return nil, nil, ErrNotArray
+	}
+
+	if len(a)%2 != 0 {
+		return nil, nil, ErrNotMap
+	}
+
+	keys := make([]string, 0, len(a)/2)
+	values := make([]string, 0, len(a)/2)
+
+	for i := 0; i < len(a); i += 2 {
+		k, err := a[i].Str()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var v string
+
+		if !a[i+1].HasType(NIL) {
+			v, err = a[i+1].Str()
+
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+
+	return keys, values, nil
+}
+
 // String returns a string representation of the Resp. This method is for
 // debugging, use Str() for reading a Str reply
 func (r *Resp) String() string {
@@ -383,6 +665,186 @@ func (r *Resp) String() string {
 	}
 }
 
+// Dump returns multi-line human-readable representation of the reply with
+// indented nested arrays. Use it for debugging complex replies.
+func (r *Resp) Dump() string {
+	var buf strings.Builder
+
+	if r != nil {
+		dumpResp(&buf, r, 0)
+	}
+
+	return buf.String()
+}
+
+// TypeName returns name of reply type (Str, BulkStr, Int, Array, Nil, ErrIO,
+// RedisErr or Unknown). Names are the same as used by String method.
+func (r *Resp) TypeName() string {
+	if r == nil {
+		return "Unknown"
+	}
+
+	switch r.typ {
+	case ERR_REDIS:
+		return "RedisErr"
+	case ERR_IO:
+		return "ErrIO"
+	case STR_BULK:
+		return "BulkStr"
+	case STR_SIMPLE:
+		return "Str"
+	case INT:
+		return "Int"
+	case NIL:
+		return "Nil"
+	case ARRAY:
+		return "Array"
+	}
+
+	return "Unknown"
+}
+
+// Attributes returns RESP3 attributes (out-of-band metadata) which preceded
+// the reply. Returns nil if there were no attributes.
+func (r *Resp) Attributes() map[string]*Resp {
+	if r == nil {
+		return nil
+	}
+
+	return r.attrs
+}
+
+// Raw returns raw reply data as it was received from Redis. Raw data is
+// available only for top-level replies read by RespReader with enabled
+// KeepRaw option, otherwise nil is returned.
+func (r *Resp) Raw() []byte {
+	if r == nil {
+		return nil
+	}
+
+	return r.raw
+}
+
+// WriteReply writes the reply to the given writer using RESP framing based on
+// reply type (unlike Cmd arguments, which are always encoded as bulk strings).
+// It can be used for implementing mock servers. I/O errors can't be encoded,
+// so ErrBadType is returned for them.
+func (r *Resp) WriteReply(w io.Writer) (int, error) {
+	return writeReply(w, make([]byte, 0, 64), r)
+}
+
+// Equal returns true if both replies have the same type and value. Arrays
+// are compared recursively, errors are compared by message.
+func (r *Resp) Equal(other *Resp) bool {
+	switch {
+	case r == nil || other == nil:
+		return r == other
+	case r.typ != other.typ:
+		return false
+	}
+
+	switch v := r.val.(type) {
+	case []byte:
+		ov, ok := other.val.([]byte)
+		return ok && bytes.Equal(v, ov)
+
+	case int64:
+		ov, ok := other.val.(int64)
+		return ok && v == ov
+
+	case error:
+		ov, ok := other.val.(error)
+		return ok && v.Error() == ov.Error()
+
+	case []Resp:
+		ov, ok := other.val.([]Resp)
+
+		if !ok || len(v) != len(ov) {
+			return false
+		}
+
+		for i := range v {
+			if !v[i].Equal(&ov[i]) {
+				return false
+			}
+		}
+
+		return true
+
+	case nil:
+		return other.val == nil
+	}
+
+	return false
+}
+
+// IsOK returns true if the reply is simple string "OK"
+func (r *Resp) IsOK() bool {
+	if r == nil || r.Err != nil || !r.HasType(STR_SIMPLE) {
+		return false
+	}
+
+	b, ok := r.val.([]byte)
+
+	return ok && string(b) == "OK"
+}
+
+// FirstError returns the first error found in the reply. Array replies are
+// walked recursively, so it can be used for checking results of pipelines and
+// transactions (EXEC) for failures. Returns nil if there are no errors.
+func (r *Resp) FirstError() error {
+	if r == nil {
+		return nil
+	}
+
+	if r.Err != nil {
+		return r.Err
+	}
+
+	a, ok := r.val.([]Resp)
+
+	if !ok {
+		return nil
+	}
+
+	for i := range a {
+		err := a[i].FirstError()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AsError returns error if the reply is not a successful value reply. For
+// error replies r.Err is returned, for NIL replies ErrRespNil.
+func (r *Resp) AsError() error {
+	switch {
+	case r == nil || r.HasType(NIL):
+		return ErrRespNil
+	case r.Err != nil:
+		return r.Err
+	case !r.HasType(STR | INT | ARRAY):
+		return ErrBadType
+	}
+
+	return nil
+}
+
+// ErrPrefix returns prefix (first word) of Redis error reply (e.g. ERR,
+// WRONGTYPE or LOADING). Returns empty string if the reply is not Redis error.
+func (r *Resp) ErrPrefix() string {
+	if r == nil || !r.HasType(ERR_REDIS) || r.Err == nil {
+		return ""
+	}
+
+	prefix, _, _ := strings.Cut(r.Err.Error(), " ")
+
+	return prefix
+}
+
 // HasType returns whether or or not the reply is of a given type
 func (r *Resp) HasType(t RespType) bool {
 	return r.typ&t > 0
@@ -390,7 +852,72 @@ func (r *Resp) HasType(t RespType) bool {
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-func bufioReadResp(r *bufio.Reader) (Resp, error) {
+// Peek returns the next n bytes without advancing the reader
+func (r *rawRecorder) Peek(n int) ([]byte, error) {
+	return r.r.Peek(n)
+}
+
+// ReadBytes reads until the first occurrence of delim in the input
+func (r *rawRecorder) ReadBytes(delim byte) ([]byte, error) {
+	b, err := r.r.ReadBytes(delim)
+	r.raw = append(r.raw, b...)
+	return b, err
+}
+
+// Read reads data into p
+func (r *rawRecorder) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.raw = append(r.raw, p[:n]...)
+	return n, err
+}
+
+// ReadByte reads and returns a single byte
+func (r *rawRecorder) ReadByte() (byte, error) {
+	c, err := r.r.ReadByte()
+
+	if err == nil {
+		r.raw = append(r.raw, c)
+	}
+
+	return c, err
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func (r *RespReader) readWithRaw() *Resp {
+	rec := &rawRecorder{r: r.r}
+	resp, err := readResp(rec, r.limits(), 1)
+
+	if err != nil {
+		resp = errToResp(ERR_IO, err)
+	} else {
+		resp.raw = rec.raw
+	}
+
+	return &resp
+}
+
+func (r *RespReader) limits() respLimits {
+	return respLimits{
+		maxDepth:       r.MaxDepth,
+		maxArrayLen:    r.MaxArrayLen,
+		largeReplySize: r.LargeReplySize,
+		onLargeReply:   r.OnLargeReply,
+	}
+}
+
+// checkLargeReply calls large reply callback if reply size exceeds soft limit
+func (l respLimits) checkLargeReply(size int64) {
+	if l.onLargeReply != nil && l.largeReplySize > 0 && size > l.largeReplySize {
+		l.onLargeReply(size)
+	}
+}
+
+func bufioReadResp(r respReader) (Resp, error) {
+	return readResp(r, respLimits{}, 1)
+}
+
+func readResp(r respReader, limits respLimits, depth int) (Resp, error) {
 	b, err := r.Peek(1)
 
 	if err != nil {
@@ -408,17 +935,25 @@ func bufioReadResp(r *bufio.Reader) (Resp, error) {
 		return readInt(r)
 
 	case prefixBulk[0]:
-		return readBulkStr(r)
+		return readBulkStr(r, limits)
 
 	case prefixArray[0]:
-		return readArray(r)
+		return readArray(r, limits, depth)
+
+	case prefixAttr[0]:
+		return readWithAttributes(r, limits, depth)
 
 	default:
 		return Resp{}, ErrBadType
 	}
 }
 
-func readSimpleStr(r *bufio.Reader) (Resp, error) {
+// readWithAttributes reads RESP3 attributes and the reply which follows them
+func readWithAttributes(r respReader, limits respLimits, depth int) (Resp, error) {
+	if limits.maxDepth > 0 && depth > limits.maxDepth {
+		return Resp{}, ErrRespTooDeep
+	}
+
 	b, err := r.ReadBytes(delimEnd)
 
 	if err != nil {
@@ -429,10 +964,78 @@ func readSimpleStr(r *bufio.Reader) (Resp, error) {
 		return Resp{}, ErrParse
 	}
 
-	return Resp{nil, b[1 : len(b)-2], STR_SIMPLE}, nil
+	size, err := strconv.ParseInt(string(b[1:len(b)-2]), 10, 64)
+
+	maxArrayLen := limits.maxArrayLen
+
+	if maxArrayLen <= 0 {
+		maxArrayLen = defaultMaxArrayLen
+	}
+
+	switch {
+	case err != nil || size < 0:
+		return Resp{}, ErrParse
+	case size > maxArrayLen/2:
+		return Resp{}, ErrRespTooBig
+	}
+
+	attrs := make(map[string]*Resp, size)
+
+	for i := int64(0); i < size; i++ {
+		k, err := readResp(r, limits, depth+1)
+
+		if err != nil {
+			return Resp{}, err
+		}
+
+		v, err := readResp(r, limits, depth+1)
+
+		if err != nil {
+			return Resp{}, err
+		}
+
+		key, err := k.Str()
+
+		if err != nil {
+			return Resp{}, ErrParse
+		}
+
+		attrs[key] = &v
+	}
+
+	// Attributes must be followed by reply, chained attributes are not allowed
+	next, err := r.Peek(1)
+
+	if err == nil && next[0] == prefixAttr[0] {
+		return Resp{}, ErrParse
+	}
+
+	m, err := readResp(r, limits, depth)
+
+	if err != nil {
+		return Resp{}, err
+	}
+
+	m.attrs = attrs
+
+	return m, nil
 }
 
-func readError(r *bufio.Reader) (Resp, error) {
+func readSimpleStr(r respReader) (Resp, error) {
+	b, err := r.ReadBytes(delimEnd)
+
+	if err != nil {
+		return Resp{}, err
+	}
+
+	if len(b) < 3 {
+		return Resp{}, ErrParse
+	}
+
+	return Resp{typ: STR_SIMPLE, val: b[1 : len(b)-2]}, nil
+}
+
+func readError(r respReader) (Resp, error) {
 	b, err := r.ReadBytes(delimEnd)
 
 	if err != nil {
@@ -448,7 +1051,7 @@ func readError(r *bufio.Reader) (Resp, error) {
 	return errToResp(ERR_REDIS, err), nil
 }
 
-func readInt(r *bufio.Reader) (Resp, error) {
+func readInt(r respReader) (Resp, error) {
 	b, err := r.ReadBytes(delimEnd)
 
 	if err != nil {
@@ -465,10 +1068,10 @@ func readInt(r *bufio.Reader) (Resp, error) {
 		return Resp{}, ErrParse
 	}
 
-	return Resp{nil, i, INT}, nil
+	return Resp{typ: INT, val: i}, nil
 }
 
-func readBulkStr(r *bufio.Reader) (Resp, error) {
+func readBulkStr(r respReader, limits respLimits) (Resp, error) {
 	b, err := r.ReadBytes(delimEnd)
 
 	if err != nil {
@@ -487,9 +1090,11 @@ func readBulkStr(r *bufio.Reader) (Resp, error) {
 	case size > 512*1024*1024:
 		return Resp{}, ErrRespTooBig
 	case size < 0:
-		return Resp{nil, nil, NIL}, nil
+		return Resp{typ: NIL}, nil
 	}
 
+	limits.checkLargeReply(size)
+
 	data := make([]byte, size)
 	b2 := data
 
@@ -521,7 +1126,11 @@ func readBulkStr(r *bufio.Reader) (Resp, error) {
 	return Resp{typ: STR_BULK, val: data}, nil
 }
 
-func readArray(r *bufio.Reader) (Resp, error) {
+func readArray(r respReader, limits respLimits, depth int) (Resp, error) {
+	if limits.maxDepth > 0 && depth > limits.maxDepth {
+		return Resp{}, ErrRespTooDeep
+	}
+
 	b, err := r.ReadBytes(delimEnd)
 
 	if err != nil {
@@ -534,17 +1143,27 @@ func readArray(r *bufio.Reader) (Resp, error) {
 
 	size, err := strconv.ParseInt(string(b[1:len(b)-2]), 10, 64)
 
+	maxArrayLen := limits.maxArrayLen
+
+	if maxArrayLen <= 0 {
+		maxArrayLen = defaultMaxArrayLen
+	}
+
 	switch {
 	case err != nil:
 		return Resp{}, ErrParse
+	case size > maxArrayLen:
+		return Resp{}, ErrRespTooBig
 	case size < 0:
-		return Resp{nil, nil, NIL}, nil
+		return Resp{typ: NIL}, nil
 	}
 
+	limits.checkLargeReply(size)
+
 	data := make([]Resp, 0)
 
 	for i := int64(0); i < size; i++ {
-		m, err := bufioReadResp(r)
+		m, err := readResp(r, limits, depth+1)
 
 		if err != nil {
 			return Resp{}, err
@@ -596,6 +1215,12 @@ func flattenedLength(mm ...any) int {
 			uint8, uint16, uint32, uint64, float32, float64, error:
 			total++
 
+		case []string:
+			total += len(m.([]string))
+
+		case [][]byte:
+			total += len(m.([][]byte))
+
 		case Resp:
 			total += flattenedLength(m.(Resp).val)
 
@@ -621,6 +1246,57 @@ func flattenedLength(mm ...any) int {
 	return total
 }
 
+// checkArgType returns error if argument (or any of its elements) has type
+// which can't be encoded without fmt.Sprint fallback
+func checkArgType(m any) error {
+	switch mt := m.(type) {
+	case []byte, string, bool, nil, int, int8, int16, int32, int64, uint,
+		uint8, uint16, uint32, uint64, float32, float64, error,
+		[]string, [][]byte:
+		return nil
+
+	case *Resp:
+		return checkArgType(mt.val)
+
+	case Resp:
+		return checkArgType(mt.val)
+	}
+
+	rm := reflect.ValueOf(m)
+
+	switch rm.Kind() {
+	case reflect.Slice:
+		for i := 0; i < rm.Len(); i++ {
+			err := checkArgType(rm.Index(i).Interface())
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case reflect.Map:
+		iter := rm.MapRange()
+
+		for iter.Next() {
+			err := checkArgType(iter.Key().Interface())
+
+			if err == nil {
+				err = checkArgType(iter.Value().Interface())
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%w %T", ErrBadArgType, m)
+}
+
 func flattenedSliceLength(m any) int {
 	var total int
 
@@ -701,6 +1377,12 @@ func writeTo(w io.Writer, buf []byte, m any) (int, error) {
 	case []any:
 		return writeInterface(w, buf, mt)
 
+	case []string:
+		return writeStrSlice(w, buf, mt)
+
+	case [][]byte:
+		return writeBytesSlice(w, buf, mt)
+
 	default:
 		switch reflect.TypeOf(m).Kind() {
 		case reflect.Slice:
@@ -714,6 +1396,99 @@ func writeTo(w io.Writer, buf []byte, m any) (int, error) {
 	return writeBytes(w, buf, []byte(fmt.Sprint(m)))
 }
 
+// writeReply writes reply with framing based on its type
+func writeReply(w io.Writer, buf []byte, r *Resp) (int, error) {
+	var err error
+	var written int
+
+	if r == nil {
+		return writeBytesHelper(w, nilFormatted, written, err)
+	}
+
+	if len(r.attrs) != 0 {
+		written, err = writeAttributes(w, buf, r.attrs)
+
+		if err != nil {
+			return written, err
+		}
+	}
+
+	switch r.typ {
+	case STR_SIMPLE:
+		written, err = writeBytesHelper(w, prefixStr, written, err)
+		written, err = writeBytesHelper(w, r.val.([]byte), written, err)
+		written, err = writeBytesHelper(w, delim, written, err)
+
+	case STR_BULK:
+		var n int
+		n, err = writeBytes(w, buf, r.val.([]byte))
+		written += n
+
+	case INT:
+		buf = strconv.AppendInt(buf[:0], r.val.(int64), 10)
+		written, err = writeBytesHelper(w, prefixInt, written, err)
+		written, err = writeBytesHelper(w, buf, written, err)
+		written, err = writeBytesHelper(w, delim, written, err)
+
+	case NIL:
+		written, err = writeBytesHelper(w, nilFormatted, written, err)
+
+	case ERR_REDIS:
+		written, err = writeBytesHelper(w, prefixErr, written, err)
+		written, err = writeBytesHelper(w, []byte(r.Err.Error()), written, err)
+		written, err = writeBytesHelper(w, delim, written, err)
+
+	case ARRAY:
+		a := r.val.([]Resp)
+
+		var n int
+		n, err = writeArrayHeader(w, buf, len(a))
+		written += n
+
+		for i := 0; i < len(a) && err == nil; i++ {
+			n, err = writeReply(w, buf, &a[i])
+			written += n
+		}
+
+	default:
+		return written, ErrBadType
+	}
+
+	return written, err
+}
+
+// writeAttributes writes RESP3 attributes sorted by key
+func writeAttributes(w io.Writer, buf []byte, attrs map[string]*Resp) (int, error) {
+	var err error
+	var written, n int
+
+	keys := make([]string, 0, len(attrs))
+
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	buf = strconv.AppendInt(buf[:0], int64(len(keys)), 10)
+
+	written, err = writeBytesHelper(w, prefixAttr, written, err)
+	written, err = writeBytesHelper(w, buf, written, err)
+	written, err = writeBytesHelper(w, delim, written, err)
+
+	for i := 0; i < len(keys) && err == nil; i++ {
+		n, err = writeStr(w, buf, keys[i])
+		written += n
+
+		if err == nil {
+			n, err = writeReply(w, buf, attrs[keys[i]])
+			written += n
+		}
+	}
+
+	return written, err
+}
+
 func writeBytesHelper(w io.Writer, b []byte, lastWritten int, lastErr error) (int, error) {
 	if lastErr != nil {
 		return lastWritten, lastErr
@@ -725,7 +1500,7 @@ func writeBytesHelper(w io.Writer, b []byte, lastWritten int, lastErr error) (in
 }
 
 func writeArrayHeader(w io.Writer, buf []byte, l int) (int, error) {
-	buf = strconv.AppendInt(buf, int64(l), 10)
+	buf = strconv.AppendInt(buf[:0], int64(l), 10)
 
 	var err error
 	var written int
@@ -782,10 +1557,25 @@ func writeInt(w io.Writer, buf []byte, i int) (int, error) {
 }
 
 func writeFloat(w io.Writer, buf []byte, f float64) (int, error) {
-	buf = strconv.AppendFloat(buf[:0], f, 'f', -1, 64)
+	buf = appendFloat(buf[:0], f)
 	return writeBytes(w, buf[len(buf):], buf)
 }
 
+// appendFloat appends float in format accepted by Redis (infinity is encoded
+// as +inf/-inf)
+func appendFloat(buf []byte, f float64) []byte {
+	switch {
+	case math.IsInf(f, 1):
+		return append(buf, "+inf"...)
+	case math.IsInf(f, -1):
+		return append(buf, "-inf"...)
+	case math.IsNaN(f):
+		return append(buf, "nan"...)
+	}
+
+	return strconv.AppendFloat(buf, f, 'f', -1, 64)
+}
+
 func writeError(w io.Writer, buf []byte, e error) (int, error) {
 	errData := []byte(e.Error())
 	return writeBytes(w, buf, errData)
@@ -808,6 +1598,36 @@ func writeInterface(w io.Writer, buf []byte, mt []any) (int, error) {
 	return totalWritten, nil
 }
 
+func writeStrSlice(w io.Writer, buf []byte, mt []string) (int, error) {
+	var totalWritten int
+
+	for _, s := range mt {
+		written, err := writeStr(w, buf, s)
+		totalWritten += written
+
+		if err != nil {
+			return totalWritten, err
+		}
+	}
+
+	return totalWritten, nil
+}
+
+func writeBytesSlice(w io.Writer, buf []byte, mt [][]byte) (int, error) {
+	var totalWritten int
+
+	for _, b := range mt {
+		written, err := writeBytes(w, buf, b)
+		totalWritten += written
+
+		if err != nil {
+			return totalWritten, err
+		}
+	}
+
+	return totalWritten, nil
+}
+
 func writeSlice(w io.Writer, buf []byte, mt any) (int, error) {
 	rm := reflect.ValueOf(mt)
 	l := rm.Len()
@@ -857,8 +1677,20 @@ func writeMap(w io.Writer, buf []byte, mt any) (int, error) {
 	return totalWritten, nil
 }
 
+// okToErr returns nil if given reply is OK and error otherwise
+func okToErr(r *Resp) error {
+	switch {
+	case r.Err != nil:
+		return r.Err
+	case !r.IsOK():
+		return ErrNotOK
+	}
+
+	return nil
+}
+
 func errToResp(t RespType, err error) Resp {
-	return Resp{err, err, t}
+	return Resp{Err: err, val: err, typ: t}
 }
 
 func arrayToString(resp *Resp) string {
@@ -875,6 +1707,32 @@ func arrayToString(resp *Resp) string {
 	return "Resp(" + kids[1:] + ")"
 }
 
+func dumpResp(buf *strings.Builder, r *Resp, depth int) {
+	switch r.typ {
+	case ERR_REDIS, ERR_IO:
+		fmt.Fprintf(buf, "%s %q\n", r.TypeName(), r.Err)
+
+	case STR_BULK, STR_SIMPLE:
+		fmt.Fprintf(buf, "%s %q\n", r.TypeName(), string(r.val.([]byte)))
+
+	case INT:
+		fmt.Fprintf(buf, "Int %d\n", r.val.(int64))
+
+	case ARRAY:
+		a := r.val.([]Resp)
+		fmt.Fprintf(buf, "Array(%d)\n", len(a))
+
+		for i := range a {
+			buf.WriteString(strings.Repeat("  ", depth+1))
+			fmt.Fprintf(buf, "%d: ", i)
+			dumpResp(buf, &a[i], depth+1)
+		}
+
+	default:
+		buf.WriteString(r.TypeName() + "\n")
+	}
+}
+
 func isTimeout(resp *Resp) bool {
 	if resp.HasType(ERR_IO) {
 		t, ok := resp.Err.(*net.OpError)
@@ -0,0 +1,109 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"strings"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// RoutingClient is client which sends read-only commands to replicas and all
+// other commands to primary
+type RoutingClient struct {
+	Primary  *Client
+	Replicas []*Client
+
+	// ReadOnlyCommands is set of commands (in upper case) which can be sent
+	// to replicas
+	ReadOnlyCommands map[string]bool
+
+	next int
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DefaultReadOnlyCommands is default set of read-only commands used by
+// RoutingClient
+var DefaultReadOnlyCommands = []string{
+	"BITCOUNT", "BITPOS", "EXISTS", "GET", "GETBIT", "GETRANGE", "HEXISTS",
+	"HGET", "HGETALL", "HKEYS", "HLEN", "HMGET", "HRANDFIELD", "HSCAN", "HVALS",
+	"LINDEX", "LLEN", "LPOS", "LRANGE", "MGET", "PTTL", "SCAN", "SCARD", "SDIFF",
+	"SINTER", "SINTERCARD", "SISMEMBER", "SMEMBERS", "SMISMEMBER", "SRANDMEMBER",
+	"SORT_RO", "SSCAN", "STRLEN", "SUNION", "TTL", "TYPE", "XLEN", "XRANGE", "XREVRANGE",
+	"ZCARD", "ZCOUNT", "ZRANDMEMBER", "ZRANGE", "ZRANGEBYLEX", "ZRANGEBYSCORE",
+	"ZRANK", "ZREVRANGE", "ZREVRANK", "ZSCAN", "ZSCORE",
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewRoutingClient creates new routing client with default set of read-only
+// commands
+func NewRoutingClient(primary *Client, replicas ...*Client) *RoutingClient {
+	readOnly := make(map[string]bool, len(DefaultReadOnlyCommands))
+
+	for _, cmd := range DefaultReadOnlyCommands {
+		readOnly[cmd] = true
+	}
+
+	return &RoutingClient{
+		Primary:          primary,
+		Replicas:         replicas,
+		ReadOnlyCommands: readOnly,
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Cmd calls the given Redis command. Read-only commands are sent to healthy
+// replicas in round-robin order. If there are no healthy replicas or replica
+// fails with I/O error, command is sent to primary. Failed replicas are skipped
+// until they are reconnected using Connect.
+func (c *RoutingClient) Cmd(cmd string, args ...any) *Resp {
+	if c.ReadOnlyCommands[strings.ToUpper(cmd)] {
+		replica := c.getReplica()
+
+		if replica != nil {
+			resp := replica.Cmd(cmd, args...)
+
+			if !resp.HasType(ERR_IO) {
+				return resp
+			}
+		}
+	}
+
+	return c.Primary.Cmd(cmd, args...)
+}
+
+// Close closes connections to primary and all replicas
+func (c *RoutingClient) Close() error {
+	var err error
+
+	for _, replica := range c.Replicas {
+		if replica.isHealthy() {
+			replica.Close()
+		}
+	}
+
+	if c.Primary.isHealthy() {
+		err = c.Primary.Close()
+	}
+
+	return err
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getReplica returns next healthy replica
+func (c *RoutingClient) getReplica() *Client {
+	for range c.Replicas {
+		replica := c.Replicas[c.next%len(c.Replicas)]
+		c.next = (c.next + 1) % len(c.Replicas)
+
+		if replica.isHealthy() {
+			return replica
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ServerHello contains info from HELLO command reply
+type ServerHello struct {
+	Server  string
+	Version string
+	Proto   int
+	ID      int64
+	Mode    string
+	Role    string
+	Modules []string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Errors
+var (
+	ErrWrongHelloResponse = errors.New("HELLO command response must have Array type")
+	ErrWrongTimeResponse  = errors.New("TIME command response must contain seconds and microseconds")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ParseHello parses HELLO command reply (field-value pairs array)
+func ParseHello(r *Resp) (*ServerHello, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	if !r.HasType(ARRAY) {
+		return nil, ErrWrongHelloResponse
+	}
+
+	items, _ := r.Array()
+
+	if len(items)%2 != 0 {
+		return nil, fmt.Errorf("Can't parse HELLO data: %v", ErrNotMap)
+	}
+
+	hello := &ServerHello{}
+
+	for i := 0; i < len(items); i += 2 {
+		field, err := items[i].Str()
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse HELLO data: %v", err)
+		}
+
+		value := items[i+1]
+
+		switch field {
+		case "server":
+			hello.Server, err = value.Str()
+		case "version":
+			hello.Version, err = value.Str()
+		case "proto":
+			hello.Proto, err = value.Int()
+		case "id":
+			hello.ID, err = value.Int64()
+		case "mode":
+			hello.Mode, err = value.Str()
+		case "role":
+			hello.Role, err = value.Str()
+		case "modules":
+			hello.Modules, err = parseHelloModules(value)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse HELLO data (%s): %v", field, err)
+		}
+	}
+
+	return hello, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Time returns current server time with microseconds precision
+func (c *Client) Time() (time.Time, error) {
+	return parseTime(c.Cmd("TIME"))
+}
+
+// WaitAOF blocks until all previous write commands are fsynced to the AOF of
+// the local Redis and/or at least the specified number of replicas (Redis 7.2+).
+// Zero timeout means blocking forever. Returns number of local Redis instances
+// (0 or 1) and number of replicas which acknowledged writes.
+func (c *Client) WaitAOF(numLocal, numReplicas int, timeout time.Duration) (int, int, error) {
+	resp := c.cmdBlocking(timeout, "WAITAOF", numLocal, numReplicas, timeout.Milliseconds())
+
+	items, err := resp.Array()
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(items) != 2 {
+		return 0, 0, ErrUnexpectedResp
+	}
+
+	localAcked, err1 := items[0].Int()
+	replicaAcked, err2 := items[1].Int()
+
+	if err1 != nil || err2 != nil {
+		return 0, 0, ErrUnexpectedResp
+	}
+
+	return localAcked, replicaAcked, nil
+}
+
+// CommandGetKeys returns keys extracted by server from the given full command.
+// It can be used for routing commands with not fixed key positions. Redis
+// returns an error for commands without key arguments.
+func (c *Client) CommandGetKeys(cmd string, args ...any) ([]string, error) {
+	return c.Cmd("COMMAND", "GETKEYS", cmd, args).List()
+}
+
+// DebugSleep makes server stall for given duration using DEBUG SLEEP command.
+// Read deadline is disabled during the call, since server doesn't reply until
+// it wakes up. DEBUG command must be enabled on server (enable-debug-command).
+func (c *Client) DebugSleep(d time.Duration) error {
+	return okToErr(c.cmdBlocking(0, "DEBUG", "SLEEP", d.Seconds()))
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// cmdBlocking calls blocking command which can wait up to given timeout before
+// reply. Read timeout is extended by command timeout (or disabled if command
+// timeout is zero).
+func (c *Client) cmdBlocking(timeout time.Duration, cmd string, args ...any) *Resp {
+	readTimeout := c.ReadTimeout
+
+	if readTimeout > 0 {
+		if timeout > 0 {
+			c.ReadTimeout += timeout
+		} else if c.conn != nil {
+			c.ReadTimeout = 0
+			c.conn.SetReadDeadline(time.Time{})
+		}
+
+		defer func() { c.ReadTimeout = readTimeout }()
+	}
+
+	return c.Cmd(cmd, args...)
+}
+
+// parseTime parses TIME command reply
+func parseTime(r *Resp) (time.Time, error) {
+	if r.Err != nil {
+		return time.Time{}, r.Err
+	}
+
+	items, err := r.Array()
+
+	if err != nil || len(items) != 2 {
+		return time.Time{}, ErrWrongTimeResponse
+	}
+
+	sec, err1 := items[0].Int64()
+	usec, err2 := items[1].Int64()
+
+	if err1 != nil || err2 != nil {
+		return time.Time{}, ErrWrongTimeResponse
+	}
+
+	return time.Unix(sec, usec*int64(time.Microsecond)), nil
+}
+
+// parseHelloModules returns names of modules from HELLO reply
+func parseHelloModules(r *Resp) ([]string, error) {
+	var result []string
+
+	err := r.ForEach(func(_ int, module *Resp) error {
+		fields, err := module.Array()
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i+1 < len(fields); i += 2 {
+			if name, _ := fields[i].Str(); name == "name" {
+				name, err = fields[i+1].Str()
+
+				if err != nil {
+					return err
+				}
+
+				result = append(result, name)
+			}
+		}
+
+		return nil
+	})
+
+	return result, err
+}
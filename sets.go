@@ -0,0 +1,90 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// SPop removes and returns up to count random members from the set stored at
+// key. Returns empty slice if key doesn't exist.
+func (c *Client) SPop(key string, count int) ([]string, error) {
+	return c.Cmd("SPOP", key, count).List()
+}
+
+// SRandMember returns up to count random members from the set stored at key.
+// If count is negative, the same member may be returned multiple times and
+// exactly -count members are returned. Returns empty slice if key doesn't exist.
+func (c *Client) SRandMember(key string, count int) ([]string, error) {
+	return c.Cmd("SRANDMEMBER", key, count).List()
+}
+
+// SInterCard returns cardinality of the intersection of all given sets
+// (Redis 7+). If limit is greater than zero, computation stops when
+// cardinality reaches the limit.
+func (c *Client) SInterCard(keys []string, limit int) (int64, error) {
+	var args []any
+
+	if limit > 0 {
+		args = []any{"LIMIT", limit}
+	}
+
+	return c.Cmd("SINTERCARD", len(keys), keys, args).Int64()
+}
+
+// SInter returns members of the intersection of all given sets
+func (c *Client) SInter(keys ...string) ([]string, error) {
+	return c.Cmd("SINTER", keys).List()
+}
+
+// SUnion returns members of the union of all given sets
+func (c *Client) SUnion(keys ...string) ([]string, error) {
+	return c.Cmd("SUNION", keys).List()
+}
+
+// SDiff returns members of the difference between the first set and all
+// successive sets
+func (c *Client) SDiff(keys ...string) ([]string, error) {
+	return c.Cmd("SDIFF", keys).List()
+}
+
+// SInterStore stores intersection of all given sets in dst and returns number
+// of elements in resulting set
+func (c *Client) SInterStore(dst string, keys ...string) (int64, error) {
+	return c.Cmd("SINTERSTORE", dst, keys).Int64()
+}
+
+// SUnionStore stores union of all given sets in dst and returns number of
+// elements in resulting set
+func (c *Client) SUnionStore(dst string, keys ...string) (int64, error) {
+	return c.Cmd("SUNIONSTORE", dst, keys).Int64()
+}
+
+// SDiffStore stores difference between the first set and all successive sets
+// in dst and returns number of elements in resulting set
+func (c *Client) SDiffStore(dst string, keys ...string) (int64, error) {
+	return c.Cmd("SDIFFSTORE", dst, keys).Int64()
+}
+
+// SIsMember returns true if member is a member of the set stored at key
+func (c *Client) SIsMember(key, member string) (bool, error) {
+	return cmdBool(c.Cmd("SISMEMBER", key, member))
+}
+
+// SMIsMember checks whether each of given members is a member of the set
+// stored at key (Redis 6.2+)
+func (c *Client) SMIsMember(key string, members ...string) ([]bool, error) {
+	items, err := c.Cmd("SMISMEMBER", key, members).Array()
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]bool, len(items))
+
+	for i, item := range items {
+		result[i], err = cmdBool(item)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,205 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"sort"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ZAddOptions contains options for ZADD command
+type ZAddOptions struct {
+	// Mode is update condition (COND_NX or COND_XX)
+	Mode string
+
+	// Compare is score comparison condition (COND_GT or COND_LT)
+	Compare string
+
+	// CH enables counting changed elements in addition to added ones
+	CH bool
+}
+
+// ZRangeOptions contains options for ZRANGEBYSCORE and ZRANGEBYLEX commands
+type ZRangeOptions struct {
+	// Offset is number of matching members to skip
+	Offset int
+
+	// Count is maximum number of returned members (0 = all)
+	Count int
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ScoreBound returns score bound for ZRANGEBYSCORE command. Infinite scores are
+// converted to -inf/+inf.
+func ScoreBound(score float64, exclusive bool) string {
+	if exclusive {
+		return string(appendFloat([]byte("("), score))
+	}
+
+	return string(appendFloat(nil, score))
+}
+
+// LexBound returns lexicographical bound for ZRANGEBYLEX command. Use "-" and
+// "+" for infinite bounds.
+func LexBound(value string, exclusive bool) string {
+	if exclusive {
+		return "(" + value
+	}
+
+	return "[" + value
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ZAdd adds all given members with their scores to the sorted set stored at
+// key. Returns number of added elements or number of added and updated elements
+// if CH option is set.
+func (c *Client) ZAdd(key string, members map[string]float64, opts ZAddOptions) (int64, error) {
+	return c.Cmd("ZADD", key, opts.args(), zaddPairs(members)).Int64()
+}
+
+// ZAddIncr increments the score of member in the sorted set stored at key
+// (ZADD with INCR option) and returns new score. ErrRespNil is returned if
+// operation was aborted due to update condition.
+func (c *Client) ZAddIncr(key, member string, increment float64, opts ZAddOptions) (float64, error) {
+	resp := c.Cmd("ZADD", key, opts.args(), "INCR", zaddPairs(map[string]float64{member: increment}))
+
+	if resp.HasType(NIL) {
+		return 0, ErrRespNil
+	}
+
+	return resp.Float64()
+}
+
+// ZRandMember returns up to count random members from the sorted set stored
+// at key (Redis 6.2+). If withScores is false, all scores in the map are zero.
+// Since the result is a map, duplicates returned for negative count are merged.
+func (c *Client) ZRandMember(key string, count int, withScores bool) (map[string]float64, error) {
+	if withScores {
+		return parseScoreMap(c.Cmd("ZRANDMEMBER", key, count, "WITHSCORES"))
+	}
+
+	members, err := c.Cmd("ZRANDMEMBER", key, count).List()
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]float64, len(members))
+
+	for _, member := range members {
+		result[member] = 0
+	}
+
+	return result, nil
+}
+
+// ZRangeByScore returns members with scores between min and max from the sorted
+// set stored at key. Bounds can be created using ScoreBound, "-inf" and "+inf"
+// are also supported.
+func (c *Client) ZRangeByScore(key, min, max string, opts ZRangeOptions) (map[string]float64, error) {
+	return parseScoreMap(c.Cmd("ZRANGEBYSCORE", key, min, max, "WITHSCORES", opts.args()))
+}
+
+// ZRangeByScoreMembers returns members with scores between min and max from
+// the sorted set stored at key ordered by score
+func (c *Client) ZRangeByScoreMembers(key, min, max string, opts ZRangeOptions) ([]string, error) {
+	return c.Cmd("ZRANGEBYSCORE", key, min, max, opts.args()).List()
+}
+
+// ZRangeByLex returns members between min and max from the sorted set stored at
+// key ordered lexicographically. Bounds can be created using LexBound, "-" and
+// "+" are also supported.
+func (c *Client) ZRangeByLex(key, min, max string, opts ZRangeOptions) ([]string, error) {
+	return c.Cmd("ZRANGEBYLEX", key, min, max, opts.args()).List()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// args returns ZADD arguments for options
+func (o ZAddOptions) args() []string {
+	var args []string
+
+	if o.Mode != "" {
+		args = append(args, o.Mode)
+	}
+
+	if o.Compare != "" {
+		args = append(args, o.Compare)
+	}
+
+	if o.CH {
+		args = append(args, "CH")
+	}
+
+	return args
+}
+
+// args returns LIMIT arguments for options
+func (o ZRangeOptions) args() []any {
+	if o.Offset <= 0 && o.Count <= 0 {
+		return nil
+	}
+
+	count := o.Count
+
+	if count <= 0 {
+		count = -1
+	}
+
+	return []any{"LIMIT", o.Offset, count}
+}
+
+// zaddPairs converts members map to score-member pairs sorted by member
+func zaddPairs(members map[string]float64) []string {
+	names := make([]string, 0, len(members))
+
+	for name := range members {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(members)*2)
+
+	for _, name := range names {
+		pairs = append(pairs, string(appendFloat(nil, members[name])), name)
+	}
+
+	return pairs
+}
+
+// parseScoreMap parses flat array of member-score pairs
+func parseScoreMap(r *Resp) (map[string]float64, error) {
+	items, err := r.Array()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items)%2 != 0 {
+		return nil, ErrNotMap
+	}
+
+	result := make(map[string]float64, len(items)/2)
+
+	for i := 0; i < len(items); i += 2 {
+		member, err := items[i].Str()
+
+		if err != nil {
+			return nil, err
+		}
+
+		score, err := items[i+1].Float64()
+
+		if err != nil {
+			return nil, err
+		}
+
+		result[member] = score
+	}
+
+	return result, nil
+}
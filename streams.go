@@ -0,0 +1,77 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// StreamEntry contains stream entry ID and fields
+type StreamEntry struct {
+	ID     string
+	Fields map[string]string
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var ErrWrongStreamResponse = errors.New("Stream command response must have Array type")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ParseStreamEntries parses XRANGE and XREVRANGE output
+func ParseStreamEntries(r *Resp) ([]StreamEntry, error) {
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	if !r.HasType(ARRAY) {
+		return nil, ErrWrongStreamResponse
+	}
+
+	items, _ := r.Array()
+	result := make([]StreamEntry, 0, len(items))
+
+	for _, item := range items {
+		entry, err := parseStreamEntry(item)
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse stream entry: %v", err)
+		}
+
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseStreamEntry parses single stream entry [id, [field, value, ...]]
+func parseStreamEntry(r *Resp) (StreamEntry, error) {
+	fields, err := r.Array()
+
+	if err != nil {
+		return StreamEntry{}, err
+	}
+
+	if len(fields) != 2 {
+		return StreamEntry{}, errors.New("entry must contain ID and fields")
+	}
+
+	id, err := fields[0].Str()
+
+	if err != nil {
+		return StreamEntry{}, err
+	}
+
+	data, err := fields[1].Map()
+
+	if err != nil {
+		return StreamEntry{}, err
+	}
+
+	return StreamEntry{ID: id, Fields: data}, nil
+}
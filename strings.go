@@ -0,0 +1,214 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"strings"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Conditions for updating keys
+const (
+	// COND_NX only sets the key if it doesn't already exist
+	COND_NX = "NX"
+
+	// COND_XX only sets the key if it already exists
+	COND_XX = "XX"
+
+	// COND_GT only updates the element if new score is greater than current
+	COND_GT = "GT"
+
+	// COND_LT only updates the element if new score is less than current
+	COND_LT = "LT"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// SetOptions contains options for SET command
+type SetOptions struct {
+	// TTL is key expiration time (EX or PX)
+	TTL time.Duration
+
+	// Mode is update condition (COND_NX or COND_XX)
+	Mode string
+
+	// KeepTTL retains the time to live associated with the key
+	KeepTTL bool
+
+	// Get enables returning old value stored at key (Redis 6.2+)
+	Get bool
+}
+
+// GetExOptions contains options for GETEX command
+type GetExOptions struct {
+	// TTL is key expiration time (EX or PX)
+	TTL time.Duration
+
+	// At is key expiration date (EXAT or PXAT)
+	At time.Time
+
+	// Persist removes the time to live associated with the key
+	Persist bool
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Set sets key to hold the given value. If Get option is set, reply contains old
+// value stored at key. Reply has type NIL if key wasn't set due to update
+// condition (without Get option) or if key didn't exist (with Get option).
+func (c *Client) Set(key string, value any, opts SetOptions) (*Resp, error) {
+	resp := c.Cmd("SET", key, value, opts.args())
+
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+
+	return resp, nil
+}
+
+// GetEx returns the value of key and optionally sets its expiration (Redis
+// 6.2+). Returns false if key doesn't exist.
+func (c *Client) GetEx(key string, opts GetExOptions) (string, bool, error) {
+	return getOptionalStr(c.Cmd("GETEX", key, opts.args()))
+}
+
+// GetDel returns the value of key and deletes the key (Redis 6.2+). Returns
+// false if key doesn't exist.
+func (c *Client) GetDel(key string) (string, bool, error) {
+	return getOptionalStr(c.Cmd("GETDEL", key))
+}
+
+// MGet returns values of all given keys. Values of keys which don't exist have
+// type NIL.
+func (c *Client) MGet(keys ...string) ([]*Resp, error) {
+	return c.Cmd("MGET", keys).Array()
+}
+
+// MGetStrings returns values of all given keys as strings. Values of keys which
+// don't exist are returned as empty strings, so use MGet or MGetBytes if you
+// need to distinguish empty values and missing keys.
+func (c *Client) MGetStrings(keys ...string) ([]string, error) {
+	return c.Cmd("MGET", keys).List()
+}
+
+// MGetBytes returns values of all given keys as byte slices. Values of keys
+// which don't exist are returned as nil.
+func (c *Client) MGetBytes(keys ...string) ([][]byte, error) {
+	return c.Cmd("MGET", keys).ListBytes()
+}
+
+// MSet sets given keys to their respective values
+func (c *Client) MSet(pairs map[string]any) error {
+	return okToErr(c.Cmd("MSET", pairs))
+}
+
+// Incr increments the number stored at key by one and returns new value
+func (c *Client) Incr(key string) (int64, error) {
+	return c.Cmd("INCR", key).Int64()
+}
+
+// IncrBy increments the number stored at key by given value and returns
+// new value
+func (c *Client) IncrBy(key string, n int64) (int64, error) {
+	return c.Cmd("INCRBY", key, n).Int64()
+}
+
+// IncrByFloat increments the floating point number stored at key by given
+// value and returns new value
+func (c *Client) IncrByFloat(key string, f float64) (float64, error) {
+	return c.Cmd("INCRBYFLOAT", key, f).Float64()
+}
+
+// Decr decrements the number stored at key by one and returns new value
+func (c *Client) Decr(key string) (int64, error) {
+	return c.Cmd("DECR", key).Int64()
+}
+
+// DecrBy decrements the number stored at key by given value and returns
+// new value
+func (c *Client) DecrBy(key string, n int64) (int64, error) {
+	return c.Cmd("DECRBY", key, n).Int64()
+}
+
+// GetRange returns substring of the string stored at key between start and end
+// offsets (inclusive). Negative offsets are counted from the end of the string
+// (-1 is the last character). Empty string is returned if key doesn't exist.
+func (c *Client) GetRange(key string, start, end int64) (string, error) {
+	return c.Cmd("GETRANGE", key, start, end).Str()
+}
+
+// SetRange overwrites part of the string stored at key starting at the given
+// offset and returns length of the string after operation. String is padded
+// with zero bytes if offset is larger than its length.
+func (c *Client) SetRange(key string, offset int64, value string) (int64, error) {
+	return c.Cmd("SETRANGE", key, offset, value).Int64()
+}
+
+// Append appends value to the string stored at key (creating key if it doesn't
+// exist) and returns length of the string after operation
+func (c *Client) Append(key string, value any) (int64, error) {
+	return c.Cmd("APPEND", key, value).Int64()
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// args returns command arguments for options
+func (o SetOptions) args() []any {
+	var args []any
+
+	if o.Mode != "" {
+		args = append(args, strings.ToUpper(o.Mode))
+	}
+
+	if o.Get {
+		args = append(args, "GET")
+	}
+
+	switch {
+	case o.KeepTTL:
+		args = append(args, "KEEPTTL")
+	case o.TTL > 0 && o.TTL%time.Second == 0:
+		args = append(args, "EX", int64(o.TTL/time.Second))
+	case o.TTL > 0:
+		args = append(args, "PX", o.TTL.Milliseconds())
+	}
+
+	return args
+}
+
+// args returns command arguments for options
+func (o GetExOptions) args() []any {
+	switch {
+	case o.Persist:
+		return []any{"PERSIST"}
+	case o.TTL > 0 && o.TTL%time.Second == 0:
+		return []any{"EX", int64(o.TTL / time.Second)}
+	case o.TTL > 0:
+		return []any{"PX", o.TTL.Milliseconds()}
+	case !o.At.IsZero() && o.At.Nanosecond() == 0:
+		return []any{"EXAT", o.At.Unix()}
+	case !o.At.IsZero():
+		return []any{"PXAT", o.At.UnixMilli()}
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getOptionalStr returns string value of reply and false if reply is nil
+func getOptionalStr(r *Resp) (string, bool, error) {
+	if r.HasType(NIL) {
+		return "", false, nil
+	}
+
+	val, err := r.Str()
+
+	if err != nil {
+		return "", false, err
+	}
+
+	return val, true, nil
+}
@@ -0,0 +1,202 @@
+package redy
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// TrackingOptions contains options for CLIENT TRACKING command
+type TrackingOptions struct {
+	// Redirect is ID of client which receives invalidation messages. Since
+	// redy uses RESP2, redirection is the only way to receive invalidations.
+	Redirect int64
+
+	// Prefixes is list of key prefixes for broadcasting mode
+	Prefixes []string
+
+	BCast  bool // Enable broadcasting mode
+	OptIn  bool // Track keys only after CLIENT CACHING yes
+	OptOut bool // Track keys unless CLIENT CACHING no is sent
+	NoLoop bool // Don't send invalidations for keys modified by this client
+}
+
+// InvalidationWatcher is consumer of client side caching invalidation messages
+type InvalidationWatcher struct {
+	client  *Client
+	ch      chan []string
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const invalidateChannel = "__redis__:invalidate"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// EnableTracking enables server assisted client side caching for connection.
+// Use Invalidations on a dedicated client and its ID (CLIENT ID) as
+// TrackingOptions.Redirect to receive invalidation messages.
+func (c *Client) EnableTracking(opts TrackingOptions) error {
+	return okToErr(c.Cmd("CLIENT", "TRACKING", "ON", opts.args()))
+}
+
+// DisableTracking disables server assisted client side caching for connection
+func (c *Client) DisableTracking() error {
+	return okToErr(c.Cmd("CLIENT", "TRACKING", "OFF"))
+}
+
+// Invalidations subscribes client to invalidation messages and returns watcher
+// with channel of lists of keys which must be evicted from local cache. After
+// this call the connection is in subscribed state and can be used only for
+// reading messages, so use a dedicated client. Read timeout is not applied to
+// this connection, since it can be idle for a long time. Channel is closed on
+// connection error (error is available in LastCritical) or if watcher is closed.
+func (c *Client) Invalidations() (*InvalidationWatcher, error) {
+	if c.conn == nil {
+		return nil, ErrNotConnected
+	}
+
+	err := c.writeRequest(req{"SUBSCRIBE", []any{invalidateChannel}})
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.conn.SetReadDeadline(time.Time{})
+
+	w := &InvalidationWatcher{
+		client:  c,
+		ch:      make(chan []string),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go w.read()
+
+	return w, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Keys returns channel with lists of keys which must be evicted from local
+// cache. Nil list means that whole cache must be flushed (e.g. after FLUSHALL).
+func (w *InvalidationWatcher) Keys() <-chan []string {
+	return w.ch
+}
+
+// Close stops watching and closes client connection
+func (w *InvalidationWatcher) Close() error {
+	w.once.Do(func() {
+		close(w.done)
+		w.client.conn.Close()
+		<-w.stopped
+		w.client.closed = true
+	})
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// args returns CLIENT TRACKING arguments for options
+func (o TrackingOptions) args() []string {
+	var args []string
+
+	if o.Redirect > 0 {
+		args = append(args, "REDIRECT", strconv.FormatInt(o.Redirect, 10))
+	}
+
+	for _, prefix := range o.Prefixes {
+		args = append(args, "PREFIX", prefix)
+	}
+
+	if o.BCast {
+		args = append(args, "BCAST")
+	}
+
+	if o.OptIn {
+		args = append(args, "OPTIN")
+	}
+
+	if o.OptOut {
+		args = append(args, "OPTOUT")
+	}
+
+	if o.NoLoop {
+		args = append(args, "NOLOOP")
+	}
+
+	return args
+}
+
+// read reads invalidation messages and sends keys to channel
+func (w *InvalidationWatcher) read() {
+	defer close(w.stopped)
+	defer close(w.ch)
+
+	c := w.client
+
+	for {
+		resp := c.respReader.Read()
+
+		if resp.Err != nil {
+			select {
+			case <-w.done:
+			default:
+				c.LastCritical = resp.Err
+				c.closed = true
+				c.conn.Close()
+			}
+
+			return
+		}
+
+		keys, ok := parseInvalidation(resp)
+
+		if !ok {
+			continue
+		}
+
+		select {
+		case w.ch <- keys:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// parseInvalidation parses invalidation message
+// [message, __redis__:invalidate, [key, key, ...]]
+func parseInvalidation(r *Resp) ([]string, bool) {
+	items, err := r.Array()
+
+	if err != nil || len(items) != 3 {
+		return nil, false
+	}
+
+	kind, _ := items[0].Str()
+	channel, _ := items[1].Str()
+
+	if kind != "message" || channel != invalidateChannel {
+		return nil, false
+	}
+
+	if items[2].HasType(NIL) {
+		return nil, true
+	}
+
+	keys, err := items[2].List()
+
+	if err != nil {
+		return nil, false
+	}
+
+	return keys, true
+}